@@ -0,0 +1,343 @@
+package janus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+	"golang.org/x/sync/errgroup"
+	"nhooyr.io/websocket"
+)
+
+// adminMsgtypes maps the "janus" field of an Admin/Monitor API response to
+// a constructor for the concrete type it should be unmarshalled into.
+// Unlike the regular Gateway, every admin response is a direct reply to a
+// request: there are no session/handle events to route.
+var adminMsgtypes = map[string]func() interface{}{
+	"success": func() interface{} { return &AdminSuccessMsg{} },
+	"error":   func() interface{} { return &ErrorMsg{} },
+}
+
+// AdminSuccessMsg is returned by the Admin/Monitor API in response to a
+// successful request. Which of its fields are populated depends on the
+// request that was sent (ListSessions only fills Sessions, and so on).
+type AdminSuccessMsg struct {
+	BaseMsg
+	Sessions []uint64               `json:"sessions,omitempty"`
+	Handles  []uint64               `json:"handles,omitempty"`
+	Info     *HandleInfo            `json:"info,omitempty"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+	Result   map[string]interface{} `json:"result,omitempty"`
+}
+
+// HandleInfo is the diagnostic blob returned by AdminGateway.HandleInfo:
+// the plugin package handling the handle, and its current ICE/DTLS/SRTP
+// and bandwidth state.
+type HandleInfo struct {
+	Session uint64 `json:"session_id"`
+	Handle  uint64 `json:"handle_id"`
+	Plugin  string `json:"plugin"`
+
+	ICEState    string `json:"ice-state,omitempty"`
+	DTLSState   string `json:"dtls-state,omitempty"`
+	SRTPProfile string `json:"srtp-profile,omitempty"`
+
+	BandwidthIn  int64 `json:"in-bandwidth,omitempty"`
+	BandwidthOut int64 `json:"out-bandwidth,omitempty"`
+}
+
+// AdminGateway represents a connection to the Janus Admin/Monitor API. It
+// is a sibling of Gateway: same JSON/transaction framing over a Transport,
+// but a different endpoint, secret (admin_secret instead of apisecret) and
+// a disjoint set of requests for inspecting sessions/handles and managing
+// stored tokens.
+type AdminGateway struct {
+	transport        Transport
+	transactions     map[xid.ID]chan interface{}
+	transactionsUsed map[xid.ID]bool
+	adminSecret      string
+
+	// Access to the above maps should be synchronized with the
+	// AdminGateway.Lock() and AdminGateway.Unlock() methods provided by
+	// the embeded sync.Mutex.
+	sync.Mutex
+
+	// LogJsonMessages enables logging of json rx/tx messages to stdout
+	LogJsonMessages bool
+
+	// DefaultCallTimeout mirrors Gateway.DefaultCallTimeout: it bounds a
+	// request only when the caller's ctx has no deadline of its own.
+	DefaultCallTimeout time.Duration
+
+	// Logger mirrors Gateway.Logger: it receives this AdminGateway's
+	// diagnostic output, or discards it if nil.
+	Logger Logger
+}
+
+func (admin *AdminGateway) logf(format string, args ...interface{}) {
+	logger := admin.Logger
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	logger.Printf(format, args...)
+}
+
+// dialAdminWebSocket is ConnectAdmin's default Transport: the same
+// wsTransport Gateway uses, dialed against the Admin/Monitor API's own
+// subprotocol.
+func dialAdminWebSocket(ctx context.Context, wsURL string) (Transport, error) {
+	opts := &websocket.DialOptions{Subprotocols: []string{"janus-admin-protocol"}}
+	conn, _, err := websocket.Dial(ctx, wsURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &wsTransport{conn: conn}, nil
+}
+
+// ConnectAdmin connects to the Janus Admin/Monitor API, over WebSocket by
+// default or another Transport selected via WithTransport (e.g.
+// NewHTTPTransport, for the same environments that need it on Connect).
+// Like Connect, it spawns a goroutine (added to the returned
+// errgroup.Group) to read and dispatch responses; callers can ignore the
+// group or call its Wait method to catch any error from it.
+func ConnectAdmin(ctx context.Context, wsURL string, adminSecret string, opts ...Option) (*AdminGateway, *errgroup.Group, error) {
+	cfg := &connectConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	transport := cfg.transport
+	if transport == nil {
+		var err error
+		transport, err = dialAdminWebSocket(ctx, wsURL)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	admin := new(AdminGateway)
+	admin.transport = transport
+	admin.transactions = make(map[xid.ID]chan interface{})
+	admin.transactionsUsed = make(map[xid.ID]bool)
+	admin.adminSecret = adminSecret
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error { return admin.recv(ctx) })
+
+	return admin, g, nil
+}
+
+// Close closes the underlying connection to the Admin API.
+func (admin *AdminGateway) Close(code websocket.StatusCode, reason string) error {
+	return admin.transport.Close(code, reason)
+}
+
+func (admin *AdminGateway) withCallTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || admin.DefaultCallTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, admin.DefaultCallTimeout)
+}
+
+func (admin *AdminGateway) send(ctx context.Context, msg map[string]interface{}, transaction chan interface{}) error {
+	guid := generateTransactionId()
+
+	msg["transaction"] = guid.String()
+	if admin.adminSecret != "" {
+		msg["admin_secret"] = admin.adminSecret
+	}
+	admin.Lock()
+	admin.transactions[guid] = transaction
+	admin.transactionsUsed[guid] = false
+	admin.Unlock()
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	if admin.LogJsonMessages {
+		var log bytes.Buffer
+		_ = json.Indent(&log, data, ">", "   ")
+		log.Write([]byte("\n"))
+		_, _ = log.WriteTo(os.Stdout)
+	}
+
+	return admin.transport.Write(ctx, data)
+}
+
+// recv should be started as a goroutine
+func (admin *AdminGateway) recv(ctx context.Context) error {
+	for {
+		var base BaseMsg
+
+		data, err := admin.transport.Read(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := json.Unmarshal(data, &base); err != nil {
+			return err
+		}
+
+		if admin.LogJsonMessages {
+			var log bytes.Buffer
+			_ = json.Indent(&log, data, "<", "   ")
+			log.Write([]byte("\n"))
+			_, _ = log.WriteTo(os.Stdout)
+		}
+
+		typeFunc, ok := adminMsgtypes[base.Type]
+		if !ok {
+			admin.logf("janus: unknown admin message type %q received", base.Type)
+			continue
+		}
+
+		msg := typeFunc()
+		if err := json.Unmarshal(data, &msg); err != nil {
+			admin.logf("janus: json.Unmarshal: %s", err)
+			return err
+		}
+
+		if base.ID == "" {
+			continue
+		}
+		id, err := xid.FromString(base.ID)
+		if err != nil {
+			continue
+		}
+
+		admin.Lock()
+		transaction := admin.transactions[id]
+		admin.Unlock()
+		if transaction == nil {
+			continue
+		}
+
+		go passMsg(admin.logf, transaction, msg)
+	}
+}
+
+// request sends req and waits for the matching success/error response,
+// honoring ctx/DefaultCallTimeout the same way Gateway's request methods
+// do.
+func (admin *AdminGateway) request(ctx context.Context, req map[string]interface{}, ch chan interface{}) (*AdminSuccessMsg, error) {
+	ctx, cancel := admin.withCallTimeout(ctx)
+	defer cancel()
+
+	if err := admin.send(ctx, req, ch); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case msg := <-ch:
+		switch msg := msg.(type) {
+		case *AdminSuccessMsg:
+			return msg, nil
+		case *ErrorMsg:
+			return nil, msg
+		}
+	}
+
+	return nil, unexpected(req["janus"].(string))
+}
+
+// ListSessions returns the session_id of every session currently known to
+// the Janus core.
+func (admin *AdminGateway) ListSessions(ctx context.Context) ([]uint64, error) {
+	req, ch := newRequest("list_sessions")
+	success, err := admin.request(ctx, req, ch)
+	if err != nil {
+		return nil, err
+	}
+	return success.Sessions, nil
+}
+
+// ListHandles returns the handle_id of every plugin handle attached within
+// sessionID.
+func (admin *AdminGateway) ListHandles(ctx context.Context, sessionID uint64) ([]uint64, error) {
+	req, ch := newRequest("list_handles")
+	req["session_id"] = sessionID
+	success, err := admin.request(ctx, req, ch)
+	if err != nil {
+		return nil, err
+	}
+	return success.Handles, nil
+}
+
+// HandleInfo returns ICE/DTLS/SRTP and bandwidth diagnostics for a single
+// plugin handle.
+func (admin *AdminGateway) HandleInfo(ctx context.Context, sessionID, handleID uint64) (*HandleInfo, error) {
+	req, ch := newRequest("handle_info")
+	req["session_id"] = sessionID
+	req["handle_id"] = handleID
+	success, err := admin.request(ctx, req, ch)
+	if err != nil {
+		return nil, err
+	}
+	if success.Info == nil {
+		return nil, unexpected("handle_info")
+	}
+	return success.Info, nil
+}
+
+// AddToken registers a new stored token with the Gateway, optionally
+// scoped to a list of plugin package names it may be used with.
+func (admin *AdminGateway) AddToken(ctx context.Context, token string, plugins []string) error {
+	req, ch := newRequest("add_token")
+	req["token"] = token
+	if len(plugins) > 0 {
+		req["plugins"] = plugins
+	}
+	_, err := admin.request(ctx, req, ch)
+	return err
+}
+
+// AllowToken extends an existing stored token to additional plugins.
+func (admin *AdminGateway) AllowToken(ctx context.Context, token string, plugins []string) error {
+	req, ch := newRequest("allow_token")
+	req["token"] = token
+	req["plugins"] = plugins
+	_, err := admin.request(ctx, req, ch)
+	return err
+}
+
+// RemoveToken revokes a stored token.
+func (admin *AdminGateway) RemoveToken(ctx context.Context, token string) error {
+	req, ch := newRequest("remove_token")
+	req["token"] = token
+	_, err := admin.request(ctx, req, ch)
+	return err
+}
+
+// StartPcap starts capturing the raw packets exchanged on a handle to a
+// pcap file on the server. folder and filename may be empty to use
+// Janus's own defaults.
+func (admin *AdminGateway) StartPcap(ctx context.Context, sessionID, handleID uint64, folder, filename string) error {
+	req, ch := newRequest("start_pcap")
+	req["session_id"] = sessionID
+	req["handle_id"] = handleID
+	if folder != "" {
+		req["folder"] = folder
+	}
+	if filename != "" {
+		req["filename"] = filename
+	}
+	_, err := admin.request(ctx, req, ch)
+	return err
+}
+
+// StopPcap stops a capture started with StartPcap.
+func (admin *AdminGateway) StopPcap(ctx context.Context, sessionID, handleID uint64) error {
+	req, ch := newRequest("stop_pcap")
+	req["session_id"] = sessionID
+	req["handle_id"] = handleID
+	_, err := admin.request(ctx, req, ch)
+	return err
+}
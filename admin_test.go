@@ -0,0 +1,215 @@
+package janus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"nhooyr.io/websocket"
+)
+
+// chanTransport is an in-memory Transport: Write enqueues the bytes a
+// test's fake server goroutine consumes, and that goroutine replies by
+// enqueueing bytes for Read to hand back to AdminGateway.recv.
+type chanTransport struct {
+	writes chan []byte
+	reads  chan []byte
+	closed chan struct{}
+}
+
+func newChanTransport() *chanTransport {
+	return &chanTransport{
+		writes: make(chan []byte, 16),
+		reads:  make(chan []byte, 16),
+		closed: make(chan struct{}),
+	}
+}
+
+func (t *chanTransport) Write(ctx context.Context, data []byte) error {
+	select {
+	case t.writes <- data:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *chanTransport) Read(ctx context.Context) ([]byte, error) {
+	select {
+	case data := <-t.reads:
+		return data, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-t.closed:
+		return nil, fmt.Errorf("janus: chanTransport closed")
+	}
+}
+
+func (t *chanTransport) Close(code websocket.StatusCode, reason string) error {
+	close(t.closed)
+	return nil
+}
+
+func (t *chanTransport) Ping(ctx context.Context) error { return nil }
+
+func newTestAdminGateway(transport Transport) *AdminGateway {
+	admin := new(AdminGateway)
+	admin.transport = transport
+	admin.transactions = make(map[xid.ID]chan interface{})
+	admin.transactionsUsed = make(map[xid.ID]bool)
+	return admin
+}
+
+// echoRequests reads one request off transport.writes, decodes it and
+// hands it to respond so the test can shape a reply keyed off the
+// request's own transaction id.
+func echoRequests(t *testing.T, transport *chanTransport, respond func(req map[string]interface{})) {
+	t.Helper()
+	go func() {
+		data := <-transport.writes
+		var req map[string]interface{}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return
+		}
+		respond(req)
+	}()
+}
+
+func TestAdminGatewaySendIncludesAdminSecret(t *testing.T) {
+	transport := newChanTransport()
+	admin := newTestAdminGateway(transport)
+	admin.adminSecret = "s3cr3t"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go admin.recv(ctx)
+
+	echoRequests(t, transport, func(req map[string]interface{}) {
+		if req["admin_secret"] != "s3cr3t" {
+			t.Errorf("admin_secret = %v, want s3cr3t", req["admin_secret"])
+		}
+		reply := fmt.Sprintf(`{"janus":"success","transaction":%q,"sessions":[]}`, req["transaction"])
+		transport.reads <- []byte(reply)
+	})
+
+	if _, err := admin.ListSessions(ctx); err != nil {
+		t.Fatalf("ListSessions returned error: %s", err)
+	}
+}
+
+func TestAdminGatewayListSessionsAndHandleInfo(t *testing.T) {
+	transport := newChanTransport()
+	admin := newTestAdminGateway(transport)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go admin.recv(ctx)
+
+	echoRequests(t, transport, func(req map[string]interface{}) {
+		reply := fmt.Sprintf(`{"janus":"success","transaction":%q,"sessions":[1,2,3]}`, req["transaction"])
+		transport.reads <- []byte(reply)
+	})
+
+	sessions, err := admin.ListSessions(ctx)
+	if err != nil {
+		t.Fatalf("ListSessions returned error: %s", err)
+	}
+	if want := []uint64{1, 2, 3}; !reflect.DeepEqual(sessions, want) {
+		t.Fatalf("ListSessions() = %v, want %v", sessions, want)
+	}
+
+	echoRequests(t, transport, func(req map[string]interface{}) {
+		if req["session_id"] != float64(1) || req["handle_id"] != float64(2) {
+			t.Errorf("request = %v, want session_id=1 handle_id=2", req)
+		}
+		reply := fmt.Sprintf(`{"janus":"success","transaction":%q,"info":{"session_id":1,"handle_id":2,"plugin":"janus.plugin.videoroom","ice-state":"connected"}}`, req["transaction"])
+		transport.reads <- []byte(reply)
+	})
+
+	info, err := admin.HandleInfo(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("HandleInfo returned error: %s", err)
+	}
+	if info.Plugin != "janus.plugin.videoroom" || info.ICEState != "connected" {
+		t.Fatalf("HandleInfo() = %+v, want plugin/ice-state populated", info)
+	}
+}
+
+func TestAdminGatewayRequestError(t *testing.T) {
+	transport := newChanTransport()
+	admin := newTestAdminGateway(transport)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go admin.recv(ctx)
+
+	echoRequests(t, transport, func(req map[string]interface{}) {
+		reply := fmt.Sprintf(`{"janus":"error","transaction":%q,"error":{"code":403,"reason":"Unauthorized"}}`, req["transaction"])
+		transport.reads <- []byte(reply)
+	})
+
+	_, err := admin.ListSessions(ctx)
+	if err == nil {
+		t.Fatal("ListSessions() = nil error, want the server's 403")
+	}
+	errMsg, ok := err.(*ErrorMsg)
+	if !ok {
+		t.Fatalf("err = %T, want *ErrorMsg", err)
+	}
+	if errMsg.Code() != ErrUnauthorized {
+		t.Fatalf("Code() = %d, want %d", errMsg.Code(), ErrUnauthorized)
+	}
+}
+
+func TestAdminGatewayTokenMethods(t *testing.T) {
+	transport := newChanTransport()
+	admin := newTestAdminGateway(transport)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go admin.recv(ctx)
+
+	calls := []struct {
+		name string
+		do   func() error
+		want string
+	}{
+		{"AddToken", func() error { return admin.AddToken(ctx, "tok", []string{"janus.plugin.videoroom"}) }, "add_token"},
+		{"AllowToken", func() error { return admin.AllowToken(ctx, "tok", []string{"janus.plugin.streaming"}) }, "allow_token"},
+		{"RemoveToken", func() error { return admin.RemoveToken(ctx, "tok") }, "remove_token"},
+	}
+
+	for _, c := range calls {
+		c := c
+		echoRequests(t, transport, func(req map[string]interface{}) {
+			if req["janus"] != c.want {
+				t.Errorf("%s sent janus=%v, want %s", c.name, req["janus"], c.want)
+			}
+			reply := fmt.Sprintf(`{"janus":"success","transaction":%q}`, req["transaction"])
+			transport.reads <- []byte(reply)
+		})
+		if err := c.do(); err != nil {
+			t.Fatalf("%s returned error: %s", c.name, err)
+		}
+	}
+}
+
+func TestAdminGatewayTimesOutWithoutResponse(t *testing.T) {
+	transport := newChanTransport()
+	admin := newTestAdminGateway(transport)
+	admin.DefaultCallTimeout = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go admin.recv(ctx)
+
+	// No responder drains transport.writes, so the call must time out on
+	// DefaultCallTimeout rather than block forever.
+	if _, err := admin.ListSessions(context.Background()); err == nil {
+		t.Fatal("ListSessions() = nil error, want a timeout")
+	}
+}
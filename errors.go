@@ -0,0 +1,97 @@
+package janus
+
+import "fmt"
+
+// ErrCode is a Janus core error code, as returned in the "error" field of an
+// ErrorMsg. See the Janus source (apierror.h) for the canonical list; only
+// the ones callers are expected to branch on are named here.
+type ErrCode int
+
+func (c ErrCode) Error() string {
+	return fmt.Sprintf("janus: error code %d", int(c))
+}
+
+// Documented Janus core error codes.
+const (
+	ErrUnauthorized            ErrCode = 403
+	ErrUnauthorizedPlugin      ErrCode = 405
+	ErrTransportSpecific       ErrCode = 450
+	ErrMissingRequest          ErrCode = 452
+	ErrUnknownRequest          ErrCode = 453
+	ErrInvalidJSON             ErrCode = 454
+	ErrInvalidJSONObject       ErrCode = 455
+	ErrMissingMandatoryElement ErrCode = 456
+	ErrInvalidRequestPath      ErrCode = 457
+	ErrSessionNotFound         ErrCode = 458
+	ErrHandleNotFound          ErrCode = 459
+	ErrPluginNotFound          ErrCode = 460
+	ErrPluginAttach            ErrCode = 461
+	ErrPluginMessage           ErrCode = 462
+	ErrPluginDetach            ErrCode = 463
+	ErrJSEPUnknownType         ErrCode = 464
+	ErrJSEPInvalidSDP          ErrCode = 465
+	ErrTrickleInvalidStream    ErrCode = 466
+	ErrInvalidElementType      ErrCode = 467
+	ErrSessionConflict         ErrCode = 468
+	ErrUnexpectedAnswer        ErrCode = 469
+	ErrTokenNotFound           ErrCode = 470
+	ErrWebRTCState             ErrCode = 471
+	ErrNotAcceptingSessions    ErrCode = 472
+	ErrUnknown                 ErrCode = 490
+)
+
+// Code returns the Janus core error code carried by this message, so
+// callers can branch on it directly instead of string-matching Reason.
+func (err *ErrorMsg) Code() ErrCode {
+	return ErrCode(err.Err.Code)
+}
+
+// Is lets callers write errors.Is(err, janus.ErrSessionNotFound) instead of
+// comparing err.Code() themselves.
+func (err *ErrorMsg) Is(target error) bool {
+	code, ok := target.(ErrCode)
+	return ok && code == err.Code()
+}
+
+// As lets callers write `var errMsg *janus.ErrorMsg; errors.As(err, &errMsg)`
+// to recover the full message (Reason, session/handle context) behind a
+// plain error value.
+func (err *ErrorMsg) As(target interface{}) bool {
+	p, ok := target.(**ErrorMsg)
+	if !ok {
+		return false
+	}
+	*p = err
+	return true
+}
+
+// handleTerminalError mirrors server-side teardown implied by certain error
+// codes: a 458 means the Gateway has already dropped the session, a 459
+// means it has already dropped the handle. Without this, callers keep
+// sending into state the server has already torn down.
+func (gateway *Gateway) handleTerminalError(base BaseMsg, errMsg *ErrorMsg) {
+	switch errMsg.Code() {
+	case ErrSessionNotFound:
+		gateway.Lock()
+		session := gateway.Sessions[base.Session]
+		delete(gateway.Sessions, base.Session)
+		gateway.Unlock()
+		if session != nil {
+			session.Events.Close()
+		}
+	case ErrHandleNotFound:
+		gateway.Lock()
+		session := gateway.Sessions[base.Session]
+		gateway.Unlock()
+		if session == nil {
+			return
+		}
+		session.Lock()
+		handle := session.Handles[base.Handle]
+		delete(session.Handles, base.Handle)
+		session.Unlock()
+		if handle != nil {
+			handle.Events.Close()
+		}
+	}
+}
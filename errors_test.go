@@ -0,0 +1,146 @@
+package janus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"nhooyr.io/websocket"
+)
+
+// scriptedTransport replays a fixed sequence of incoming messages from
+// Read, then blocks until ctx is cancelled, simulating a connection that
+// stays open after delivering everything it has queued.
+type scriptedTransport struct {
+	mu       sync.Mutex
+	messages [][]byte
+}
+
+func (t *scriptedTransport) Write(ctx context.Context, data []byte) error { return nil }
+
+func (t *scriptedTransport) Read(ctx context.Context) ([]byte, error) {
+	t.mu.Lock()
+	if len(t.messages) > 0 {
+		msg := t.messages[0]
+		t.messages = t.messages[1:]
+		t.mu.Unlock()
+		return msg, nil
+	}
+	t.mu.Unlock()
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (t *scriptedTransport) Close(code websocket.StatusCode, reason string) error { return nil }
+func (t *scriptedTransport) Ping(ctx context.Context) error                      { return nil }
+
+func TestRecvSessionNotFoundClosesSessionEvents(t *testing.T) {
+	gateway := newTestGateway()
+
+	session := new(Session)
+	session.gateway = gateway
+	session.ID = 42
+	session.Handles = make(map[uint64]*Handle)
+	session.Events = NewEventQueue(2, DropOldest)
+	gateway.Sessions[42] = session
+
+	id := xid.New()
+	ch := make(chan interface{}, 1)
+	gateway.transactions[id] = ch
+
+	body := fmt.Sprintf(`{"janus":"error","session_id":42,"transaction":%q,"error":{"code":458,"reason":"No such session"}}`, id.String())
+	gateway.transport = &scriptedTransport{messages: [][]byte{[]byte(body)}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- gateway.recv(ctx) }()
+
+	select {
+	case msg := <-ch:
+		if _, ok := msg.(*ErrorMsg); !ok {
+			t.Fatalf("got %T, want *ErrorMsg", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("transaction was never notified")
+	}
+
+	gateway.Lock()
+	_, ok := gateway.Sessions[42]
+	gateway.Unlock()
+	if ok {
+		t.Fatal("session 42 still present after a 458 error, want it removed")
+	}
+
+	select {
+	case _, open := <-session.Events.Events():
+		if open {
+			t.Fatal("session.Events delivered a message instead of closing")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("session.Events was never closed after a 458 error")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestRecvHandleNotFoundClosesHandleEvents(t *testing.T) {
+	gateway := newTestGateway()
+
+	session := new(Session)
+	session.gateway = gateway
+	session.ID = 42
+	session.Handles = make(map[uint64]*Handle)
+	session.Events = NewEventQueue(2, DropOldest)
+	gateway.Sessions[42] = session
+
+	handle := new(Handle)
+	handle.session = session
+	handle.ID = 7
+	handle.Events = NewEventQueue(2, DropOldest)
+	session.Handles[7] = handle
+
+	id := xid.New()
+	ch := make(chan interface{}, 1)
+	gateway.transactions[id] = ch
+
+	body := fmt.Sprintf(`{"janus":"error","session_id":42,"sender":7,"transaction":%q,"error":{"code":459,"reason":"No such handle"}}`, id.String())
+	gateway.transport = &scriptedTransport{messages: [][]byte{[]byte(body)}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- gateway.recv(ctx) }()
+
+	select {
+	case msg := <-ch:
+		if _, ok := msg.(*ErrorMsg); !ok {
+			t.Fatalf("got %T, want *ErrorMsg", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("transaction was never notified")
+	}
+
+	session.Lock()
+	_, ok := session.Handles[7]
+	session.Unlock()
+	if ok {
+		t.Fatal("handle 7 still present after a 459 error, want it removed")
+	}
+
+	select {
+	case _, open := <-handle.Events.Events():
+		if open {
+			t.Fatal("handle.Events delivered a message instead of closing")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handle.Events was never closed after a 459 error")
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,233 @@
+package janus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// HTTPTransport speaks Janus's plain HTTP/REST binding instead of
+// WebSocket: POST {baseURL} (or {baseURL}/{session_id}, or
+// {baseURL}/{session_id}/{handle_id}) to send a request,
+// and GET {baseURL}/{session_id}?maxev=N&rid=... to long-poll for
+// asynchronous events, once a session exists. Use it via WithTransport
+// when WebSocket upgrades are blocked (corporate proxies, some CDNs).
+//
+// HTTPTransport doesn't implement Redialer: a Close()d HTTPTransport is
+// done for good, so it can't be combined with ReconnectOptions.
+type HTTPTransport struct {
+	baseURL string
+	client  *http.Client
+
+	// MaxEvents bounds how many queued events a single long-poll GET may
+	// return at once (Janus's maxev parameter). Defaults to 10.
+	MaxEvents int
+
+	events chan []byte
+	done   chan struct{}
+	closed sync.Once
+
+	mu      sync.Mutex
+	polling map[uint64]context.CancelFunc
+}
+
+// NewHTTPTransport creates an HTTPTransport targeting baseURL, e.g.
+// "https://janus.example.com/janus". apisecret, like every other field
+// Gateway.send sets on the outgoing message, is already injected before
+// the request reaches the Transport; HTTPTransport has no secret of its
+// own to configure.
+func NewHTTPTransport(baseURL string) *HTTPTransport {
+	return &HTTPTransport{
+		baseURL:   baseURL,
+		client:    http.DefaultClient,
+		MaxEvents: 10,
+		events:    make(chan []byte, 32),
+		done:      make(chan struct{}),
+		polling:   make(map[uint64]context.CancelFunc),
+	}
+}
+
+type wireRequest struct {
+	Janus   string `json:"janus"`
+	Session uint64 `json:"session_id"`
+	Handle  uint64 `json:"handle_id"`
+}
+
+// Write POSTs a single JSON request. A request carrying a handle_id is
+// posted to {baseURL}/{session_id}/{handle_id}, one carrying only a
+// session_id goes to {baseURL}/{session_id}, and one with neither (info,
+// create) goes straight to {baseURL}.
+func (t *HTTPTransport) Write(ctx context.Context, data []byte) error {
+	var req wireRequest
+	_ = json.Unmarshal(data, &req)
+
+	url := t.baseURL
+	switch {
+	case req.Session != 0 && req.Handle != 0:
+		url = fmt.Sprintf("%s/%d/%d", t.baseURL, req.Session, req.Handle)
+	case req.Session != 0:
+		url = fmt.Sprintf("%s/%d", t.baseURL, req.Session)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	// The response to this POST is itself a message (success/error) that
+	// needs to flow through the same Read() the long-poll loop feeds, so
+	// the Gateway's recv loop can correlate it by transaction exactly as
+	// it would over WebSocket.
+	select {
+	case t.events <- body:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	var ack struct {
+		Type string `json:"janus"`
+	}
+	_ = json.Unmarshal(body, &ack)
+
+	switch {
+	case req.Janus == "create" && ack.Type == "success":
+		// A successful "create" response is our first sight of a
+		// session_id; start long-polling it for the async events
+		// WebSocket would have delivered on the same connection.
+		var success SuccessMsg
+		if json.Unmarshal(body, &success) == nil && success.Data.ID != 0 {
+			t.trackSession(success.Data.ID)
+		}
+	case req.Janus == "destroy" && req.Session != 0 && (ack.Type == "success" || ack.Type == "ack"):
+		// The session no longer exists server-side; stop polling it
+		// instead of long-polling a session that is gone forever.
+		t.untrackSession(req.Session)
+	}
+
+	return nil
+}
+
+// Read returns the next buffered response or long-poll event.
+func (t *HTTPTransport) Read(ctx context.Context) ([]byte, error) {
+	select {
+	case data := <-t.events:
+		return data, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-t.done:
+		return nil, fmt.Errorf("janus: http transport closed")
+	}
+}
+
+// Close stops every long-poll loop. code/reason are ignored; HTTP has no
+// equivalent close handshake.
+func (t *HTTPTransport) Close(code websocket.StatusCode, reason string) error {
+	t.mu.Lock()
+	for _, cancel := range t.polling {
+		cancel()
+	}
+	t.polling = make(map[uint64]context.CancelFunc)
+	t.mu.Unlock()
+	t.closed.Do(func() { close(t.done) })
+	return nil
+}
+
+// Ping is a no-op: Janus's HTTP binding has no ping frame, and
+// KeepAliveSender's "keepalive" request already keeps sessions alive.
+func (t *HTTPTransport) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (t *HTTPTransport) trackSession(sessionID uint64) {
+	t.mu.Lock()
+	if _, ok := t.polling[sessionID]; ok {
+		t.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.polling[sessionID] = cancel
+	t.mu.Unlock()
+
+	go t.pollSession(ctx, sessionID)
+}
+
+// untrackSession stops the long-poll loop for sessionID, if one is
+// running. Called once Write sees that session get destroyed.
+func (t *HTTPTransport) untrackSession(sessionID uint64) {
+	t.mu.Lock()
+	cancel, ok := t.polling[sessionID]
+	delete(t.polling, sessionID)
+	t.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+func (t *HTTPTransport) pollSession(ctx context.Context, sessionID uint64) {
+	for rid := 0; ; rid++ {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		url := fmt.Sprintf("%s/%d?maxev=%d&rid=%d", t.baseURL, sessionID, t.MaxEvents, rid)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return
+		}
+
+		resp, err := t.client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			time.Sleep(time.Second)
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			continue
+		}
+
+		var events []json.RawMessage
+		if err := json.Unmarshal(body, &events); err == nil {
+			for _, ev := range events {
+				select {
+				case t.events <- []byte(ev):
+				case <-ctx.Done():
+					return
+				}
+			}
+			continue
+		}
+
+		select {
+		case t.events <- body:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
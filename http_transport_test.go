@@ -0,0 +1,98 @@
+package janus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHTTPTransportWriteURLIncludesHandleID(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.Write([]byte(`{"janus":"ack"}`))
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(server.URL)
+	defer transport.Close(0, "")
+
+	ctx := context.Background()
+
+	msg, _ := json.Marshal(map[string]interface{}{"janus": "trickle", "session_id": uint64(1), "handle_id": uint64(2)})
+	if err := transport.Write(ctx, msg); err != nil {
+		t.Fatalf("Write returned error: %s", err)
+	}
+	readCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if _, err := transport.Read(readCtx); err != nil {
+		t.Fatalf("Read returned error: %s", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := "/1/2"
+	if len(paths) != 1 || paths[0] != want {
+		t.Fatalf("POST path = %v, want [%s]", paths, want)
+	}
+}
+
+func TestHTTPTransportUntracksSessionOnDestroy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			var req wireRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			switch req.Janus {
+			case "create":
+				w.Write([]byte(`{"janus":"success","data":{"id":42}}`))
+			case "destroy":
+				w.Write([]byte(`{"janus":"success"}`))
+			default:
+				w.Write([]byte(`{"janus":"ack"}`))
+			}
+		default: // long-poll GET
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(server.URL)
+	defer transport.Close(0, "")
+
+	ctx := context.Background()
+
+	create, _ := json.Marshal(map[string]interface{}{"janus": "create"})
+	if err := transport.Write(ctx, create); err != nil {
+		t.Fatalf("create Write returned error: %s", err)
+	}
+	transport.Read(ctx) // drain the create response
+
+	transport.mu.Lock()
+	_, polling := transport.polling[42]
+	transport.mu.Unlock()
+	if !polling {
+		t.Fatal("session 42 was never tracked for long-polling after create")
+	}
+
+	destroy, _ := json.Marshal(map[string]interface{}{"janus": "destroy", "session_id": uint64(42)})
+	if err := transport.Write(ctx, destroy); err != nil {
+		t.Fatalf("destroy Write returned error: %s", err)
+	}
+	transport.Read(ctx) // drain the destroy response
+
+	transport.mu.Lock()
+	_, stillPolling := transport.polling[42]
+	transport.mu.Unlock()
+	if stillPolling {
+		t.Fatal("session 42 is still tracked for long-polling after destroy")
+	}
+}
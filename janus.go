@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/xid"
@@ -18,8 +19,19 @@ import (
 
 // The message types are defined in RFC 6455, section 11.8.
 const (
-	pingMessage     = 9
-	timeoutDuration = 1 * time.Second
+	pingMessage = 9
+
+	// transactionDeliverTimeout bounds how long passMsg waits for a
+	// request/response call to read its transaction's reply before giving
+	// up on it. This is unrelated to the EventQueue backing
+	// Session.Events/Handle.Events, which never drops on a timer.
+	transactionDeliverTimeout = 1 * time.Second
+
+	// defaultSessionQueueCapacity and defaultHandleQueueCapacity are used
+	// when Gateway.EventQueueOptions leaves the corresponding capacity at
+	// its zero value.
+	defaultSessionQueueCapacity = 2
+	defaultHandleQueueCapacity  = 8
 )
 
 func unexpected(request string) error {
@@ -41,20 +53,97 @@ type Gateway struct {
 	// and Gateway.Unlock() methods provided by the embeded sync.Mutex.
 	sync.Mutex
 
-	conn             *websocket.Conn
+	transport        Transport
+	dial             func(ctx context.Context) (Transport, error)
+	url              string
 	transactions     map[xid.ID]chan interface{}
 	transactionsUsed map[xid.ID]bool
 	apiSecret	 string
 
+	// ReconnectOptions configures automatic reconnection when the
+	// transport drops. The zero value disables reconnection: a transport
+	// error is returned to the errgroup as before.
+	ReconnectOptions ReconnectOptions
+	onReconnect       func(ev ReconnectEvent)
+
+	connMu        sync.Mutex
+	connCond      *sync.Cond
+	state         gatewayState
+	pendingWriters int
+
 	// LogJsonMessages enables logging of json rx/tx messages to stdout
 	LogJsonMessages bool
+
+	// DefaultCallTimeout bounds how long request/response calls (Info,
+	// Create, Attach, KeepAlive, Destroy, Request, Message, Trickle,
+	// TrickleMany, Detach) wait for the Gateway, but only when the ctx
+	// passed in by the caller has no deadline of its own. If the caller
+	// already used context.WithTimeout/WithDeadline, that takes
+	// precedence. Zero means no bound is applied and calls block until
+	// the Gateway responds or ctx is cancelled.
+	DefaultCallTimeout time.Duration
+
+	// EventQueueOptions configures the EventQueue created for every
+	// Session and Handle's Events channel. The zero value keeps the
+	// previous default buffer sizes (2 for a Session, 8 for a Handle)
+	// with DropOldest overflow.
+	EventQueueOptions EventQueueOptions
+
+	// Logger receives this Gateway's diagnostic output. Nil (the zero
+	// value) discards it, matching the old behavior minus the stdout
+	// prints.
+	Logger Logger
+
+	messagesReceived  uint64
+	reconnectAttempts uint64
+	pingRTTNanos      int64
+}
+
+// EventQueueOptions configures the capacity and overflow behavior of the
+// EventQueue backing every Session and Handle created by a Gateway.
+type EventQueueOptions struct {
+	// SessionCapacity and HandleCapacity bound how many undelivered
+	// messages a Session or Handle will buffer before Policy kicks in.
+	// Zero uses the package defaults.
+	SessionCapacity int
+	HandleCapacity  int
+
+	// Policy is applied once a queue reaches its capacity. The zero
+	// value is DropOldest.
+	Policy OverflowPolicy
+}
+
+func (gateway *Gateway) sessionQueueCapacity() int {
+	if c := gateway.EventQueueOptions.SessionCapacity; c > 0 {
+		return c
+	}
+	return defaultSessionQueueCapacity
+}
+
+func (gateway *Gateway) handleQueueCapacity() int {
+	if c := gateway.EventQueueOptions.HandleCapacity; c > 0 {
+		return c
+	}
+	return defaultHandleQueueCapacity
+}
+
+// withCallTimeout derives a ctx bounded by DefaultCallTimeout, but only when
+// the incoming ctx doesn't already carry a deadline. The returned cancel
+// func should always be deferred by the caller.
+func (gateway *Gateway) withCallTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || gateway.DefaultCallTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, gateway.DefaultCallTimeout)
 }
 
 func generateTransactionId() xid.ID {
 	return xid.New()
 }
 
-// Connect initiates a websock connection with the Janus Gateway
+// Connect initiates a connection with the Janus Gateway. By default this is
+// a WebSocket connection to wsURL; pass janus.WithTransport to speak a
+// different binding (e.g. janus.NewHTTPTransport) instead.
 //
 // It will also spawn two goroutines to maintain the connection
 // One is for sending Websocket ping messages periodically
@@ -66,20 +155,43 @@ func generateTransactionId() xid.ID {
 // methods, AND CATCH ANY ERRORS THAT OCCUR inside of them.
 // The readme has links to more info on errgroup.
 //
-func Connect(ctx context.Context, wsURL string, secret string) (*Gateway, *errgroup.Group, error) {
+func Connect(ctx context.Context, wsURL string, secret string, opts ...Option) (*Gateway, *errgroup.Group, error) {
+
+	cfg := &connectConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
 
-	opts := &websocket.DialOptions{Subprotocols: []string{"janus-protocol"}}
-	conn, _, err := websocket.Dial(ctx, wsURL, opts)
+	var transport Transport
+	var err error
+	if cfg.transport != nil {
+		transport = cfg.transport
+	} else {
+		transport, err = dialWebSocket(ctx, wsURL)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
 
 	gateway := new(Gateway)
-	gateway.conn = conn
+	gateway.transport = transport
+	gateway.dial = func(ctx context.Context) (Transport, error) {
+		if cfg.transport != nil {
+			redialer, ok := cfg.transport.(Redialer)
+			if !ok {
+				return nil, fmt.Errorf("janus: ReconnectOptions can't redial %T, which was passed via WithTransport and doesn't implement janus.Redialer", cfg.transport)
+			}
+			return redialer.Redial(ctx)
+		}
+		return dialWebSocket(ctx, wsURL)
+	}
+	gateway.url = wsURL
 	gateway.transactions = make(map[xid.ID]chan interface{})
 	gateway.transactionsUsed = make(map[xid.ID]bool)
 	gateway.Sessions = make(map[uint64]*Session)
 	gateway.apiSecret = secret
+	gateway.connCond = sync.NewCond(&gateway.connMu)
+	gateway.state = stateConnected
 
 	// By returning errgroup.Group callers can wait for
 	// any errors that occur in these goroutines. Powerful.
@@ -88,28 +200,77 @@ func Connect(ctx context.Context, wsURL string, secret string) (*Gateway, *errgr
 	// the other one will be cancelled.
 	g, ctx := errgroup.WithContext(ctx)
 
-	g.Go(func() error { return gateway.ping(ctx) })
-	g.Go(func() error { return gateway.recv(ctx) })
+	g.Go(func() error { return gateway.superviseTransport(ctx, gateway.ping) })
+	g.Go(func() error { return gateway.superviseTransport(ctx, gateway.recv) })
 
 	return gateway, g, nil
 }
 
+// superviseTransport runs one of the long-lived transport loops (ping or
+// recv) and, when it returns a transport error, hands off to reconnect
+// instead of immediately failing the errgroup. It only returns once run
+// succeeds via ctx cancellation or reconnection is disabled/exhausted.
+func (gateway *Gateway) superviseTransport(ctx context.Context, run func(context.Context) error) error {
+	for {
+		err := run(ctx)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		if !gateway.reconnect(ctx, err) {
+			return err
+		}
+	}
+}
+
+// currentTransport returns the active Transport, safe to call while a
+// reconnect may be swapping it out from under ping/recv/send.
+func (gateway *Gateway) currentTransport() Transport {
+	gateway.connMu.Lock()
+	defer gateway.connMu.Unlock()
+	return gateway.transport
+}
+
 // WaitForGroup is an example of
 // wait and catch errors from the Connect() function
-func WaitForGroup(g *errgroup.Group) error {
+func WaitForGroup(gateway *Gateway, g *errgroup.Group) error {
 	err := g.Wait() //finish when
 	if err != nil {
-		println(fmt.Sprintf("janus-session ended with error %v", err)) //stderr
+		gateway.logf("janus-session ended with error %v", err)
 	}
 	return err
 }
 
-// Close closes the underlying connection to the Gateway.
+// Close closes the underlying connection to the Gateway. It also marks the
+// Gateway stateClosed first, so the transport error this produces in
+// ping/recv is treated as the deliberate shutdown it is instead of
+// triggering ReconnectOptions' auto-reconnect: without this, a Close on a
+// reconnect-enabled Gateway made it redial instead of shutting down.
 func (gateway *Gateway) Close(code websocket.StatusCode, reason string) error {
-	return gateway.conn.Close(code, reason)
+	gateway.connMu.Lock()
+	gateway.state = stateClosed
+	if gateway.connCond != nil {
+		gateway.connCond.Broadcast()
+	}
+	gateway.connMu.Unlock()
+
+	return gateway.currentTransport().Close(code, reason)
 }
 
 func (gateway *Gateway) send(ctx context.Context, msg map[string]interface{}, transaction chan interface{}) error {
+	if err := gateway.waitForConnected(ctx); err != nil {
+		return err
+	}
+	return gateway.rawSend(ctx, msg, transaction)
+}
+
+// rawSend writes msg to the current transport without first blocking on
+// waitForConnected. resume's claim/create/attach calls run on the
+// redialLoop goroutine itself, between a successful redial and reconnect
+// flipping the Gateway back out of stateReconnecting, so routing them
+// through waitForConnected would have them wait on a connCond that
+// nothing broadcasts until they return: a permanent deadlock for any
+// reconnect with at least one live Session.
+func (gateway *Gateway) rawSend(ctx context.Context, msg map[string]interface{}, transaction chan interface{}) error {
 	guid := generateTransactionId()
 
 	msg["transaction"] = guid.String()
@@ -134,16 +295,22 @@ func (gateway *Gateway) send(ctx context.Context, msg map[string]interface{}, tr
 		_, _ = log.WriteTo(os.Stdout)
 	}
 
-	err = gateway.conn.Write(ctx, websocket.MessageText, data)
+	err = gateway.currentTransport().Write(ctx, data)
 	return err
 
 }
 
-func passMsg(ch chan interface{}, msg interface{}) {
+// passMsg delivers msg to a transaction's one-shot reply channel. Unlike
+// Session/Handle event delivery (see EventQueue), a transaction channel
+// has exactly one reader that is already waiting on it, so a goroutine
+// per in-flight transaction with a bounded timeout is fine: it only leaks
+// for the rare case where the caller's ctx expired right before the
+// reply arrived.
+func passMsg(logf func(format string, args ...interface{}), ch chan interface{}, msg interface{}) {
 	select {
 	case ch <- msg:
-	case <-time.After(timeoutDuration):
-		println("no reader/discarded %#v", msg)
+	case <-time.After(transactionDeliverTimeout):
+		logf("janus: no reader for transaction reply, discarded %#v", msg)
 	}
 }
 
@@ -159,11 +326,12 @@ func (gateway *Gateway) ping(ctx context.Context) error {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-ticker.C:
-			//fmt.Println("wsping on websock")
-			err := gateway.conn.Write(ctx, pingMessage, []byte{})
+			start := time.Now()
+			err := gateway.currentTransport().Ping(ctx)
 			if err != nil {
 				return err
 			}
+			atomic.StoreInt64(&gateway.pingRTTNanos, int64(time.Since(start)))
 		}
 	}
 }
@@ -197,7 +365,7 @@ func (gateway *Gateway) recv(ctx context.Context) error {
 		// Decode to Msg struct
 		var base BaseMsg
 
-		_, data, err := gateway.conn.Read(ctx)
+		data, err := gateway.currentTransport().Read(ctx)
 		if err != nil {
 			return err
 		}
@@ -214,9 +382,11 @@ func (gateway *Gateway) recv(ctx context.Context) error {
 			_, _ = log.WriteTo(os.Stdout)
 		}
 
+		atomic.AddUint64(&gateway.messagesReceived, 1)
+
 		typeFunc, ok := msgtypes[base.Type]
 		if !ok {
-			fmt.Printf("Unknown message type received!\n")
+			gateway.logf("janus: unknown message type %q received", base.Type)
 			// 122220 use continue, not return error.
 			// hopefully best trade-off between fail-early
 			// robust run-time behavior
@@ -225,7 +395,7 @@ func (gateway *Gateway) recv(ctx context.Context) error {
 
 		msg := typeFunc()
 		if err := json.Unmarshal(data, &msg); err != nil {
-			fmt.Printf("json.Unmarshal: %s\n", err)
+			gateway.logf("janus: json.Unmarshal: %s", err)
 			// 122220 change from continue to return err
 			// if this happens, it probably means we have a serious error
 			return err
@@ -241,16 +411,29 @@ func (gateway *Gateway) recv(ctx context.Context) error {
 
 		// Pass message on from here
 		if base.ID == "" || transactionUsed {
-			// Is this a Handle event?
+			// Is this a Handle event, or a session-level notification
+			// (e.g. "timeout") that carries no sender/handle id?
 			if base.Handle == 0 {
-				// Error()
+				gateway.Lock()
+				session := gateway.Sessions[base.Session]
+				gateway.Unlock()
+				if session == nil {
+					gateway.logf("janus: unable to deliver message, session %d gone", base.Session)
+					// 122220 leave as continue, not return err
+					continue
+				}
+
+				// Pass msg. session.Events is a single-writer EventQueue,
+				// so this applies its OverflowPolicy instead of spawning
+				// a goroutine per message.
+				session.Events.Push(msg)
 			} else {
 				// Lookup Session
 				gateway.Lock()
 				session := gateway.Sessions[base.Session]
 				gateway.Unlock()
 				if session == nil {
-					fmt.Printf("Unable to deliver message. Session gone?\n")
+					gateway.logf("janus: unable to deliver message, session %d gone", base.Session)
 					// 122220 leave as continue, not return err
 					continue
 				}
@@ -260,13 +443,15 @@ func (gateway *Gateway) recv(ctx context.Context) error {
 				handle := session.Handles[base.Handle]
 				session.Unlock()
 				if handle == nil {
-					fmt.Printf("Unable to deliver message. Handle gone?\n")
+					gateway.logf("janus: unable to deliver message, handle %d gone", base.Handle)
 					// 122220 leave as continue, not return err
 					continue
 				}
 
-				// Pass msg
-				go passMsg(handle.Events, msg)
+				// Pass msg. handle.Events is a single-writer EventQueue,
+				// so this applies its OverflowPolicy instead of spawning
+				// a goroutine per message.
+				handle.Events.Push(msg)
 			}
 		} else {
 			id, _ := xid.FromString(base.ID)
@@ -282,8 +467,12 @@ func (gateway *Gateway) recv(ctx context.Context) error {
 				return fmt.Errorf("null transaction")
 			}
 
+			if errMsg, ok := msg.(*ErrorMsg); ok {
+				gateway.handleTerminalError(base, errMsg)
+			}
+
 			// Pass msg
-			go passMsg(transaction, msg)
+			go passMsg(gateway.logf, transaction, msg)
 		}
 	}
 }
@@ -291,6 +480,9 @@ func (gateway *Gateway) recv(ctx context.Context) error {
 // Info sends an info request to the Gateway.
 // On success, an InfoMsg will be returned and error will be nil.
 func (gateway *Gateway) Info(ctx context.Context) (*InfoMsg, error) {
+	ctx, cancel := gateway.withCallTimeout(ctx)
+	defer cancel()
+
 	req, ch := newRequest("info")
 	err := gateway.send(ctx, req, ch)
 	if err != nil {
@@ -298,8 +490,6 @@ func (gateway *Gateway) Info(ctx context.Context) (*InfoMsg, error) {
 	}
 
 	select {
-	case <-time.After(timeoutDuration):
-		return nil,fmt.Errorf("timeout waiting for response to 'info'")
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	case msg := <-ch:
@@ -317,6 +507,9 @@ func (gateway *Gateway) Info(ctx context.Context) (*InfoMsg, error) {
 // Create sends a create request to the Gateway.
 // On success, a new Session will be returned and error will be nil.
 func (gateway *Gateway) Create(ctx context.Context) (*Session, error) {
+	ctx, cancel := gateway.withCallTimeout(ctx)
+	defer cancel()
+
 	req, ch := newRequest("create")
 	err := gateway.send(ctx, req, ch)
 	if err != nil {
@@ -325,8 +518,6 @@ func (gateway *Gateway) Create(ctx context.Context) (*Session, error) {
 
 	var success *SuccessMsg
 	select {
-	case <-time.After(timeoutDuration):
-		return nil,fmt.Errorf("timeout waiting for response to 'create'")
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	case msg := <-ch:
@@ -343,7 +534,7 @@ func (gateway *Gateway) Create(ctx context.Context) (*Session, error) {
 	session.gateway = gateway
 	session.ID = success.Data.ID
 	session.Handles = make(map[uint64]*Handle)
-	session.Events = make(chan interface{}, 2)
+	session.Events = NewEventQueue(gateway.sessionQueueCapacity(), gateway.EventQueueOptions.Policy)
 
 	// Store this session
 	gateway.Lock()
@@ -355,13 +546,20 @@ func (gateway *Gateway) Create(ctx context.Context) (*Session, error) {
 
 // Session represents a session instance on the Janus Gateway.
 type Session struct {
-	// ID is the session_id of this session
+	// ID is the session_id of this session. A reconnect that fails to
+	// claim this session back may rewrite it under gateway.Lock() (see
+	// reconnect.go's reattach); use the id() accessor instead of reading
+	// this field directly from anywhere that isn't already holding that
+	// lock.
 	ID uint64
 
 	// Handles is a map of plugin handles within this session
 	Handles map[uint64]*Handle
 
-	Events chan interface{}
+	// Events is the EventQueue asynchronous events for this session are
+	// delivered through. Call Events.Events() for the channel to
+	// range/select over.
+	Events *EventQueue
 
 	// Access to the Handles map should be synchronized with the Session.Lock()
 	// and Session.Unlock() methods provided by the embeded sync.Mutex.
@@ -370,15 +568,34 @@ type Session struct {
 	gateway *Gateway
 }
 
+// id returns session.ID, synchronized with the same Gateway.Lock a
+// reconnect's reattach uses to rewrite it, so a send racing a reattach
+// can't read it unsynchronized.
+func (session *Session) id() uint64 {
+	session.gateway.Lock()
+	defer session.gateway.Unlock()
+	return session.ID
+}
+
 func (session *Session) send(ctx context.Context, msg map[string]interface{}, transaction chan interface{}) error {
-	msg["session_id"] = session.ID
+	msg["session_id"] = session.id()
 	return session.gateway.send(ctx, msg, transaction)
 }
 
+// rawSend mirrors Gateway.rawSend, for the same reattach-during-resume
+// reason: it must not block on waitForConnected.
+func (session *Session) rawSend(ctx context.Context, msg map[string]interface{}, transaction chan interface{}) error {
+	msg["session_id"] = session.id()
+	return session.gateway.rawSend(ctx, msg, transaction)
+}
+
 // Attach sends an attach request to the Gateway within this session.
 // plugin should be the unique string of the plugin to attach to.
 // On success, a new Handle will be returned and error will be nil.
 func (session *Session) Attach(ctx context.Context, plugin string) (*Handle, error) {
+	ctx, cancel := session.gateway.withCallTimeout(ctx)
+	defer cancel()
+
 	req, ch := newRequest("attach")
 	req["plugin"] = plugin
 	err := session.send(ctx, req, ch)
@@ -388,8 +605,6 @@ func (session *Session) Attach(ctx context.Context, plugin string) (*Handle, err
 
 	var success *SuccessMsg
 	select {
-	case <-time.After(timeoutDuration):
-		return nil,fmt.Errorf("timeout waiting for response to 'attach'")
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	case msg := <-ch:
@@ -404,7 +619,8 @@ func (session *Session) Attach(ctx context.Context, plugin string) (*Handle, err
 	handle := new(Handle)
 	handle.session = session
 	handle.ID = success.Data.ID
-	handle.Events = make(chan interface{}, 8)
+	handle.plugin = plugin
+	handle.Events = NewEventQueue(session.gateway.handleQueueCapacity(), session.gateway.EventQueueOptions.Policy)
 
 	session.Lock()
 	session.Handles[handle.ID] = handle
@@ -416,6 +632,9 @@ func (session *Session) Attach(ctx context.Context, plugin string) (*Handle, err
 // KeepAlive sends a keep-alive request to the Gateway.
 // On success, an AckMsg will be returned and error will be nil.
 func (session *Session) KeepAlive(ctx context.Context) (*AckMsg, error) {
+	ctx, cancel := session.gateway.withCallTimeout(ctx)
+	defer cancel()
+
 	req, ch := newRequest("keepalive")
 	err := session.send(ctx, req, ch)
 	if err != nil {
@@ -423,8 +642,6 @@ func (session *Session) KeepAlive(ctx context.Context) (*AckMsg, error) {
 	}
 
 	select {
-	case <-time.After(timeoutDuration):
-		return nil,fmt.Errorf("timeout waiting for response to 'keepalive'")
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	case msg := <-ch:
@@ -443,6 +660,9 @@ func (session *Session) KeepAlive(ctx context.Context) (*AckMsg, error) {
 // On success, the Session will be removed from the Gateway.Sessions map, an
 // AckMsg will be returned and error will be nil.
 func (session *Session) Destroy(ctx context.Context) (*AckMsg, error) {
+	ctx, cancel := session.gateway.withCallTimeout(ctx)
+	defer cancel()
+
 	req, ch := newRequest("destroy")
 	err := session.send(ctx, req, ch)
 	if err != nil {
@@ -451,8 +671,6 @@ func (session *Session) Destroy(ctx context.Context) (*AckMsg, error) {
 
 	var ack *AckMsg
 	select {
-	case <-time.After(timeoutDuration):
-		return nil,fmt.Errorf("timeout waiting for response to 'destroy'")
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	case msg := <-ch:
@@ -469,12 +687,18 @@ func (session *Session) Destroy(ctx context.Context) (*AckMsg, error) {
 	delete(session.gateway.Sessions, session.ID)
 	session.gateway.Unlock()
 
+	session.Events.Close()
+
 	return ack, nil
 }
 
 // Handle represents a handle to a plugin instance on the Gateway.
 type Handle struct {
-	// ID is the handle_id of this plugin handle
+	// ID is the handle_id of this plugin handle. A reconnect that fails
+	// to claim the owning session back may rewrite it under
+	// session.Lock() (see reconnect.go's reattach); use the id()
+	// accessor instead of reading this field directly from anywhere
+	// that isn't already holding that lock.
 	ID uint64
 
 	// Type   // pub  or sub
@@ -483,15 +707,31 @@ type Handle struct {
 	//User   // Userid
 	User string
 
-	// Events is a receive only channel that can be used to receive events
-	// related to this handle from the gateway.
-	Events chan interface{}
+	// Events is the EventQueue this handle's asynchronous events (and any
+	// jsep renegotiation offers) are delivered through. Call
+	// Events.Events() for the channel to range/select over.
+	Events *EventQueue
+
+	// plugin is the plugin package this handle was attached to. It is
+	// kept around so a reconnect that fails to claim back the owning
+	// session can re-Attach the same plugin under the server-assigned
+	// session and handle id.
+	plugin string
 
 	session *Session
 }
 
+// id returns handle.ID, synchronized with the owning Session.Lock a
+// reconnect's reattach uses to rewrite it, so a send racing a reattach
+// can't read it unsynchronized.
+func (handle *Handle) id() uint64 {
+	handle.session.Lock()
+	defer handle.session.Unlock()
+	return handle.ID
+}
+
 func (handle *Handle) send(ctx context.Context, msg map[string]interface{}, transaction chan interface{}) error {
-	msg["handle_id"] = handle.ID
+	msg["handle_id"] = handle.id()
 	err := handle.session.send(ctx, msg, transaction)
 	return err
 
@@ -499,6 +739,9 @@ func (handle *Handle) send(ctx context.Context, msg map[string]interface{}, tran
 
 // Request sends a sync request
 func (handle *Handle) Request(ctx context.Context, body interface{}) (*SuccessMsg, error) {
+	ctx, cancel := handle.session.gateway.withCallTimeout(ctx)
+	defer cancel()
+
 	req, ch := newRequest("message")
 	if body != nil {
 		req["body"] = body
@@ -509,8 +752,6 @@ func (handle *Handle) Request(ctx context.Context, body interface{}) (*SuccessMs
 	}
 
 	select {
-	case <-time.After(timeoutDuration):
-		return nil,fmt.Errorf("timeout waiting for response to 'message'")
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	case msg := <-ch:
@@ -530,6 +771,9 @@ func (handle *Handle) Request(ctx context.Context, body interface{}) (*SuccessMs
 // contain an optional SDP offer/answer to establish a WebRTC PeerConnection.
 // On success, an EventMsg will be returned and error will be nil.
 func (handle *Handle) Message(ctx context.Context, body, jsep interface{}) (*EventMsg, error) {
+	ctx, cancel := handle.session.gateway.withCallTimeout(ctx)
+	defer cancel()
+
 	req, ch := newRequest("message")
 	if body != nil {
 		req["body"] = body
@@ -545,8 +789,6 @@ func (handle *Handle) Message(ctx context.Context, body, jsep interface{}) (*Eve
 GetMessage: // No tears..
 
 	select {
-	case <-time.After(timeoutDuration):
-		return nil,fmt.Errorf("timeout waiting for response to 'message'")
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	case msg := <-ch:
@@ -572,6 +814,9 @@ GetMessage: // No tears..
 //		}
 // On success, an AckMsg will be returned and error will be nil.
 func (handle *Handle) Trickle(ctx context.Context, candidate interface{}) (*AckMsg, error) {
+	ctx, cancel := handle.session.gateway.withCallTimeout(ctx)
+	defer cancel()
+
 	req, ch := newRequest("trickle")
 	req["candidate"] = candidate
 	err := handle.send(ctx, req, ch)
@@ -580,8 +825,6 @@ func (handle *Handle) Trickle(ctx context.Context, candidate interface{}) (*AckM
 	}
 
 	select {
-	case <-time.After(timeoutDuration):
-		return nil,fmt.Errorf("timeout waiting for response to 'trickle'")
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	case msg := <-ch:
@@ -601,6 +844,9 @@ func (handle *Handle) Trickle(ctx context.Context, candidate interface{}) (*AckM
 // candidates should be an array of ICE candidates.
 // On success, an AckMsg will be returned and error will be nil.
 func (handle *Handle) TrickleMany(ctx context.Context, candidates interface{}) (*AckMsg, error) {
+	ctx, cancel := handle.session.gateway.withCallTimeout(ctx)
+	defer cancel()
+
 	req, ch := newRequest("trickle")
 	req["candidates"] = candidates
 	err := handle.send(ctx, req, ch)
@@ -609,8 +855,6 @@ func (handle *Handle) TrickleMany(ctx context.Context, candidates interface{}) (
 	}
 
 	select {
-	case <-time.After(timeoutDuration):
-		return nil,fmt.Errorf("timeout waiting for response to 'trickle'/many")
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	case msg := <-ch:
@@ -628,6 +872,9 @@ func (handle *Handle) TrickleMany(ctx context.Context, candidates interface{}) (
 // Detach sends a detach request to the Gateway to remove this handle.
 // On success, an AckMsg will be returned and error will be nil.
 func (handle *Handle) Detach(ctx context.Context) (*AckMsg, error) {
+	ctx, cancel := handle.session.gateway.withCallTimeout(ctx)
+	defer cancel()
+
 	req, ch := newRequest("detach")
 	err := handle.send(ctx, req, ch)
 	if err != nil {
@@ -636,8 +883,6 @@ func (handle *Handle) Detach(ctx context.Context) (*AckMsg, error) {
 
 	var ack *AckMsg
 	select {
-	case <-time.After(timeoutDuration):
-		return nil,fmt.Errorf("timeout waiting for response to 'detach'")
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	case msg := <-ch:
@@ -654,5 +899,7 @@ func (handle *Handle) Detach(ctx context.Context) (*AckMsg, error) {
 	delete(handle.session.Handles, handle.ID)
 	handle.session.Unlock()
 
+	handle.Events.Close()
+
 	return ack, nil
 }
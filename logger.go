@@ -0,0 +1,23 @@
+package janus
+
+// Logger receives diagnostic output from a Gateway — the kind of thing
+// this package used to send straight to stdout via println/fmt.Printf.
+// The zero Gateway logs nothing; set Gateway.Logger to plug in
+// log/slog, zap, logrus, or similar.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Printf(string, ...interface{}) {}
+
+// logf routes a diagnostic message through gateway.Logger, or discards it
+// if none was set.
+func (gateway *Gateway) logf(format string, args ...interface{}) {
+	logger := gateway.Logger
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	logger.Printf(format, args...)
+}
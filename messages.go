@@ -0,0 +1,89 @@
+package janus
+
+import "fmt"
+
+// BaseMsg is the minimal set of fields present on every message received
+// from the Gateway. It is unmarshalled first so recv can decide which
+// concrete message type to unmarshal the rest of the payload into, and
+// where to route it (by transaction, session and handle).
+type BaseMsg struct {
+	Type    string `json:"janus"`
+	Session uint64 `json:"session_id"`
+	Handle  uint64 `json:"sender"`
+	ID      string `json:"transaction"`
+}
+
+// msgtypes maps the "janus" field of an incoming message to a constructor
+// for the concrete type it should be unmarshalled into.
+var msgtypes = map[string]func() interface{}{
+	"error":   func() interface{} { return &ErrorMsg{} },
+	"success": func() interface{} { return &SuccessMsg{} },
+	"ack":     func() interface{} { return &AckMsg{} },
+	"event":   func() interface{} { return &EventMsg{} },
+	"detached": func() interface{} { return &AckMsg{} },
+	"webrtcup": func() interface{} { return &AckMsg{} },
+	"media":    func() interface{} { return &AckMsg{} },
+	"hangup":   func() interface{} { return &AckMsg{} },
+	"slowlink": func() interface{} { return &AckMsg{} },
+	"timeout":  func() interface{} { return &AckMsg{} },
+	"server_info": func() interface{} { return &InfoMsg{} },
+}
+
+// AckMsg is returned by the Gateway to acknowledge a request that doesn't
+// carry any data of its own (keepalive, trickle, destroy, detach, ...).
+type AckMsg struct {
+	BaseMsg
+}
+
+// SuccessMsg is returned by the Gateway in response to requests that
+// allocate something (create, attach), and to synchronous plugin requests
+// made via Handle.Request (in which case Plugindata carries the result
+// instead of Data).
+type SuccessMsg struct {
+	BaseMsg
+	Data struct {
+		ID uint64 `json:"id"`
+	} `json:"data"`
+	Plugindata PluginData `json:"plugindata,omitempty"`
+}
+
+// PluginData carries the plugin-specific payload attached to an EventMsg.
+type PluginData struct {
+	Plugin string                 `json:"plugin"`
+	Data   map[string]interface{} `json:"data"`
+}
+
+// EventMsg is an asynchronous event pushed by a plugin, optionally carrying
+// an SDP offer/answer in Jsep.
+type EventMsg struct {
+	BaseMsg
+	Plugindata PluginData             `json:"plugindata"`
+	Jsep       map[string]interface{} `json:"jsep,omitempty"`
+}
+
+// InfoMsg is returned in response to Gateway.Info.
+type InfoMsg struct {
+	BaseMsg
+	Name          string `json:"name"`
+	Version       int    `json:"version"`
+	VersionString string `json:"version_string"`
+	Author        string `json:"author"`
+}
+
+// ErrorMsg is returned by the Gateway whenever a request fails. It
+// implements the error interface so it can be returned directly from the
+// request methods on Gateway, Session and Handle.
+type ErrorMsg struct {
+	BaseMsg
+	Err ErrorData `json:"error"`
+}
+
+// ErrorData is the "error" object embedded in an ErrorMsg.
+type ErrorData struct {
+	Code   int    `json:"code"`
+	Reason string `json:"reason"`
+}
+
+func (err *ErrorMsg) Error() string {
+	return fmt.Sprintf("janus-go: request failed (code %d): %s", err.Err.Code, err.Err.Reason)
+}
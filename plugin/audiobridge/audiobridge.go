@@ -0,0 +1,132 @@
+// Package audiobridge is a typed wrapper around janus.Handle for the
+// Janus AudioBridge plugin.
+package audiobridge
+
+import (
+	"context"
+
+	janus "github.com/jafzaf/janus-go"
+	"github.com/jafzaf/janus-go/plugin/internal/wire"
+	"github.com/pion/webrtc/v3"
+)
+
+// Plugin is the plugin package name to pass to Session.Attach when
+// creating a Handle for use with this client.
+const Plugin = "janus.plugin.audiobridge"
+
+// Client wraps a Handle already attached to Plugin with typed
+// request/response helpers.
+type Client struct {
+	Handle *janus.Handle
+}
+
+// New wraps handle, which must already be attached to Plugin.
+func New(handle *janus.Handle) *Client {
+	return &Client{Handle: handle}
+}
+
+// CreateRoomRequest configures a new AudioBridge room.
+type CreateRoomRequest struct {
+	Room         uint64 `json:"room,omitempty"`
+	Description  string `json:"description,omitempty"`
+	SamplingRate int    `json:"sampling_rate,omitempty"`
+	IsPrivate    bool   `json:"is_private,omitempty"`
+	Secret       string `json:"secret,omitempty"`
+	PIN          string `json:"pin,omitempty"`
+	Record       bool   `json:"record,omitempty"`
+	RecordFile   string `json:"record_file,omitempty"`
+}
+
+// Room identifies an AudioBridge room, as returned by Create.
+type Room struct {
+	Room uint64 `json:"room"`
+}
+
+// Create asks the plugin to set up a new room.
+func (c *Client) Create(ctx context.Context, req CreateRoomRequest) (*Room, error) {
+	body := struct {
+		Request string `json:"request"`
+		CreateRoomRequest
+	}{"create", req}
+
+	resp, err := c.Handle.Request(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var room Room
+	if err := wire.Decode(resp.Plugindata.Data, &room); err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// JoinRequest joins an AudioBridge room.
+type JoinRequest struct {
+	Room    uint64 `json:"room"`
+	Display string `json:"display,omitempty"`
+	Muted   bool   `json:"muted,omitempty"`
+	PIN     string `json:"pin,omitempty"`
+}
+
+// Join sends a join request together with an SDP offer and returns the
+// plugin's SDP answer.
+func (c *Client) Join(ctx context.Context, req JoinRequest, offer *webrtc.SessionDescription) (*webrtc.SessionDescription, error) {
+	body := struct {
+		Request string `json:"request"`
+		JoinRequest
+	}{"join", req}
+
+	event, err := c.Handle.Message(ctx, body, offer)
+	if err != nil {
+		return nil, err
+	}
+	return wire.DecodeJsep(event.Jsep), nil
+}
+
+// Participant describes a participant currently in a room, as returned by
+// ListParticipants.
+type Participant struct {
+	ID      uint64 `json:"id"`
+	Display string `json:"display,omitempty"`
+	Muted   bool   `json:"muted,omitempty"`
+	Talking bool   `json:"talking,omitempty"`
+}
+
+// ListParticipants lists the current participants in room.
+func (c *Client) ListParticipants(ctx context.Context, room uint64) ([]Participant, error) {
+	body := map[string]interface{}{"request": "listparticipants", "room": room}
+	resp, err := c.Handle.Request(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Participants []Participant `json:"participants"`
+	}
+	if err := wire.Decode(resp.Plugindata.Data, &out); err != nil {
+		return nil, err
+	}
+	return out.Participants, nil
+}
+
+// Kick removes participantID from room.
+func (c *Client) Kick(ctx context.Context, room, participantID uint64) error {
+	body := map[string]interface{}{"request": "kick", "room": room, "id": participantID}
+	_, err := c.Handle.Request(ctx, body)
+	return err
+}
+
+// Mute mutes participantID in room.
+func (c *Client) Mute(ctx context.Context, room, participantID uint64) error {
+	body := map[string]interface{}{"request": "mute", "room": room, "id": participantID}
+	_, err := c.Handle.Request(ctx, body)
+	return err
+}
+
+// Unmute unmutes participantID in room.
+func (c *Client) Unmute(ctx context.Context, room, participantID uint64) error {
+	body := map[string]interface{}{"request": "unmute", "room": room, "id": participantID}
+	_, err := c.Handle.Request(ctx, body)
+	return err
+}
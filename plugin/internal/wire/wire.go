@@ -0,0 +1,38 @@
+// Package wire holds the small JSON helpers shared by the plugin
+// subpackages, for converting between the map[string]interface{} blobs
+// Handle.Events/Request/Message deliver and each package's typed
+// request/response structs.
+package wire
+
+import (
+	"encoding/json"
+
+	"github.com/pion/webrtc/v3"
+)
+
+// Decode re-marshals data (as delivered in plugindata.data) and unmarshals
+// it into out.
+func Decode(data map[string]interface{}, out interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// DecodeJsep converts the jsep map handed back on an EventMsg into a
+// *webrtc.SessionDescription, or nil if there wasn't one.
+func DecodeJsep(jsep map[string]interface{}) *webrtc.SessionDescription {
+	if jsep == nil {
+		return nil
+	}
+	raw, err := json.Marshal(jsep)
+	if err != nil {
+		return nil
+	}
+	var sdp webrtc.SessionDescription
+	if err := json.Unmarshal(raw, &sdp); err != nil {
+		return nil
+	}
+	return &sdp
+}
@@ -0,0 +1,101 @@
+// Package sip is a typed wrapper around janus.Handle for the Janus SIP
+// plugin.
+package sip
+
+import (
+	"context"
+	"fmt"
+
+	janus "github.com/jafzaf/janus-go"
+	"github.com/jafzaf/janus-go/plugin/internal/wire"
+	"github.com/pion/webrtc/v3"
+)
+
+// Plugin is the plugin package name to pass to Session.Attach when
+// creating a Handle for use with this client.
+const Plugin = "janus.plugin.sip"
+
+// Client wraps a Handle already attached to Plugin with typed
+// request/response helpers.
+type Client struct {
+	Handle *janus.Handle
+}
+
+// New wraps handle, which must already be attached to Plugin.
+func New(handle *janus.Handle) *Client {
+	return &Client{Handle: handle}
+}
+
+// RegisterRequest registers the SIP account to place and receive calls
+// with.
+type RegisterRequest struct {
+	Username    string `json:"username"`
+	Secret      string `json:"secret,omitempty"`
+	Proxy       string `json:"proxy,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// RegisterResult is the plugin's report of the outcome of a Register
+// request, delivered as the async event that follows the request's
+// immediate ack.
+type RegisterResult struct {
+	Event  string `json:"event"` // "registering", "registered", or "registration_failed"
+	Code   int    `json:"code,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Register registers req with the SIP server. Like Call/Accept/Hangup,
+// the plugin acks the request immediately and reports the real outcome
+// asynchronously, so this waits on the ack-then-event pattern via
+// Handle.Message rather than Handle.Request.
+func (c *Client) Register(ctx context.Context, req RegisterRequest) error {
+	body := struct {
+		Request string `json:"request"`
+		RegisterRequest
+	}{"register", req}
+
+	event, err := c.Handle.Message(ctx, body, nil)
+	if err != nil {
+		return err
+	}
+
+	var out struct {
+		Result RegisterResult `json:"result"`
+	}
+	if err := wire.Decode(event.Plugindata.Data, &out); err != nil {
+		return err
+	}
+	if out.Result.Event == "registration_failed" {
+		return fmt.Errorf("sip: registration failed (code %d): %s", out.Result.Code, out.Result.Reason)
+	}
+	return nil
+}
+
+// CallRequest places an outgoing call.
+type CallRequest struct {
+	URI string `json:"uri"`
+}
+
+// Call places a call to req.URI, sending offer as the SDP offer.
+func (c *Client) Call(ctx context.Context, req CallRequest, offer webrtc.SessionDescription) error {
+	body := struct {
+		Request string `json:"request"`
+		CallRequest
+	}{"call", req}
+	_, err := c.Handle.Message(ctx, body, offer)
+	return err
+}
+
+// Accept answers an incoming call with answer.
+func (c *Client) Accept(ctx context.Context, answer webrtc.SessionDescription) error {
+	body := map[string]interface{}{"request": "accept"}
+	_, err := c.Handle.Message(ctx, body, answer)
+	return err
+}
+
+// Hangup ends the current call.
+func (c *Client) Hangup(ctx context.Context) error {
+	body := map[string]interface{}{"request": "hangup"}
+	_, err := c.Handle.Message(ctx, body, nil)
+	return err
+}
@@ -0,0 +1,115 @@
+// Package streaming is a typed wrapper around janus.Handle for the Janus
+// Streaming plugin.
+package streaming
+
+import (
+	"context"
+
+	janus "github.com/jafzaf/janus-go"
+	"github.com/jafzaf/janus-go/plugin/internal/wire"
+	"github.com/pion/webrtc/v3"
+)
+
+// Plugin is the plugin package name to pass to Session.Attach when
+// creating a Handle for use with this client.
+const Plugin = "janus.plugin.streaming"
+
+// Client wraps a Handle already attached to Plugin with typed
+// request/response helpers.
+type Client struct {
+	Handle *janus.Handle
+}
+
+// New wraps handle, which must already be attached to Plugin.
+func New(handle *janus.Handle) *Client {
+	return &Client{Handle: handle}
+}
+
+// Mountpoint describes a streaming mountpoint, as returned by
+// CreateMountpoint and List.
+type Mountpoint struct {
+	ID          uint64 `json:"id"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Enabled     bool   `json:"enabled,omitempty"`
+}
+
+// CreateRtpMountpointRequest configures a new RTP mountpoint that relays
+// an externally fed RTP stream.
+type CreateRtpMountpointRequest struct {
+	ID          uint64 `json:"id,omitempty"`
+	Type        string `json:"type,omitempty"` // defaults to "rtp" server-side
+	Description string `json:"description,omitempty"`
+	Audio       bool   `json:"audio,omitempty"`
+	Video       bool   `json:"video,omitempty"`
+	AudioPort   int    `json:"audioport,omitempty"`
+	VideoPort   int    `json:"videoport,omitempty"`
+	Secret      string `json:"secret,omitempty"`
+	PIN         string `json:"pin,omitempty"`
+	Permanent   bool   `json:"permanent,omitempty"`
+}
+
+// CreateMountpoint asks the plugin to set up a new RTP mountpoint.
+func (c *Client) CreateMountpoint(ctx context.Context, req CreateRtpMountpointRequest) (*Mountpoint, error) {
+	body := struct {
+		Request string `json:"request"`
+		CreateRtpMountpointRequest
+	}{"create", req}
+
+	resp, err := c.Handle.Request(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Stream Mountpoint `json:"stream"`
+	}
+	if err := wire.Decode(resp.Plugindata.Data, &out); err != nil {
+		return nil, err
+	}
+	return &out.Stream, nil
+}
+
+// List lists the mountpoints currently known to the plugin.
+func (c *Client) List(ctx context.Context) ([]Mountpoint, error) {
+	body := map[string]interface{}{"request": "list"}
+	resp, err := c.Handle.Request(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		List []Mountpoint `json:"list"`
+	}
+	if err := wire.Decode(resp.Plugindata.Data, &out); err != nil {
+		return nil, err
+	}
+	return out.List, nil
+}
+
+// Watch starts watching mountpointID and returns the plugin's SDP offer.
+func (c *Client) Watch(ctx context.Context, mountpointID uint64, pin string) (*webrtc.SessionDescription, error) {
+	body := map[string]interface{}{"request": "watch", "id": mountpointID}
+	if pin != "" {
+		body["pin"] = pin
+	}
+	event, err := c.Handle.Message(ctx, body, nil)
+	if err != nil {
+		return nil, err
+	}
+	return wire.DecodeJsep(event.Jsep), nil
+}
+
+// Start answers the plugin's offer from Watch, starting the stream.
+func (c *Client) Start(ctx context.Context, answer webrtc.SessionDescription) error {
+	body := map[string]interface{}{"request": "start"}
+	_, err := c.Handle.Message(ctx, body, answer)
+	return err
+}
+
+// Stop stops the stream started by Start.
+func (c *Client) Stop(ctx context.Context) error {
+	body := map[string]interface{}{"request": "stop"}
+	_, err := c.Handle.Message(ctx, body, nil)
+	return err
+}
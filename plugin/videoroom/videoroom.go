@@ -0,0 +1,325 @@
+// Package videoroom is a typed wrapper around janus.Handle for the Janus
+// VideoRoom plugin, so callers don't have to hand-assemble
+// map[string]interface{} request bodies or hand-parse plugindata.data on
+// the way back.
+package videoroom
+
+import (
+	"encoding/json"
+
+	janus "github.com/jafzaf/janus-go"
+	"github.com/jafzaf/janus-go/plugin/internal/wire"
+	"github.com/pion/webrtc/v3"
+
+	"context"
+)
+
+// Plugin is the plugin package name to pass to Session.Attach when
+// creating a Handle for use with this client.
+const Plugin = "janus.plugin.videoroom"
+
+// Client wraps a Handle already attached to Plugin with typed
+// request/response helpers.
+type Client struct {
+	Handle *janus.Handle
+}
+
+// New wraps handle, which must already be attached to Plugin.
+func New(handle *janus.Handle) *Client {
+	return &Client{Handle: handle}
+}
+
+// CreateRoomRequest configures a new VideoRoom.
+type CreateRoomRequest struct {
+	Room          uint64 `json:"room,omitempty"`
+	Description   string `json:"description,omitempty"`
+	PublishersMax int    `json:"publishers,omitempty"`
+	Bitrate       int    `json:"bitrate,omitempty"`
+	IsPrivate     bool   `json:"is_private,omitempty"`
+	Secret        string `json:"secret,omitempty"`
+	PIN           string `json:"pin,omitempty"`
+	Record        bool   `json:"record,omitempty"`
+	RecDir        string `json:"rec_dir,omitempty"`
+}
+
+// Room identifies a VideoRoom, as returned by Create.
+type Room struct {
+	Room uint64 `json:"room"`
+}
+
+// Create asks the plugin to set up a new room.
+func (c *Client) Create(ctx context.Context, req CreateRoomRequest) (*Room, error) {
+	body := struct {
+		Request string `json:"request"`
+		CreateRoomRequest
+	}{"create", req}
+
+	resp, err := c.Handle.Request(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var room Room
+	if err := wire.Decode(resp.Plugindata.Data, &room); err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// JoinRequest joins a room either as a publisher or as a subscriber to
+// Feed.
+type JoinRequest struct {
+	Room    uint64 `json:"room"`
+	PType   string `json:"ptype"` // "publisher" or "subscriber"
+	Display string `json:"display,omitempty"`
+	Feed    uint64 `json:"feed,omitempty"` // subscriber only
+	PIN     string `json:"pin,omitempty"`
+}
+
+// JoinResponse is the plugin's acknowledgement of a Join.
+type JoinResponse struct {
+	Room        uint64 `json:"room"`
+	Description string `json:"description,omitempty"`
+	ID          uint64 `json:"id,omitempty"`
+}
+
+// Join sends a join request and returns the plugin's response together
+// with any SDP offer/answer it attached.
+func (c *Client) Join(ctx context.Context, req JoinRequest) (*JoinResponse, *webrtc.SessionDescription, error) {
+	body := struct {
+		Request string `json:"request"`
+		JoinRequest
+	}{"join", req}
+
+	event, err := c.Handle.Message(ctx, body, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var joined JoinResponse
+	if err := wire.Decode(event.Plugindata.Data, &joined); err != nil {
+		return nil, nil, err
+	}
+	return &joined, wire.DecodeJsep(event.Jsep), nil
+}
+
+// Publish sends an SDP offer to become (or renegotiate as) a publisher and
+// returns the plugin's SDP answer.
+func (c *Client) Publish(ctx context.Context, offer webrtc.SessionDescription) (*webrtc.SessionDescription, error) {
+	body := map[string]interface{}{"request": "configure", "audio": true, "video": true}
+	event, err := c.Handle.Message(ctx, body, offer)
+	if err != nil {
+		return nil, err
+	}
+	return wire.DecodeJsep(event.Jsep), nil
+}
+
+// Participant describes a publisher currently in a room, as returned by
+// ListParticipants.
+type Participant struct {
+	ID      uint64 `json:"id"`
+	Display string `json:"display,omitempty"`
+	Talking bool   `json:"talking,omitempty"`
+}
+
+// ListParticipants lists the current publishers in room.
+func (c *Client) ListParticipants(ctx context.Context, room uint64) ([]Participant, error) {
+	body := map[string]interface{}{"request": "listparticipants", "room": room}
+	resp, err := c.Handle.Request(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Participants []Participant `json:"participants"`
+	}
+	if err := wire.Decode(resp.Plugindata.Data, &out); err != nil {
+		return nil, err
+	}
+	return out.Participants, nil
+}
+
+// Kick removes feedID from room.
+func (c *Client) Kick(ctx context.Context, room, feedID uint64, secret string) error {
+	body := map[string]interface{}{"request": "kick", "room": room, "id": feedID}
+	if secret != "" {
+		body["secret"] = secret
+	}
+	_, err := c.Handle.Request(ctx, body)
+	return err
+}
+
+// RtpForwardRequest configures an RTP forwarder for a publisher's feed.
+type RtpForwardRequest struct {
+	Room        uint64 `json:"room"`
+	PublisherID uint64 `json:"publisher_id"`
+	Host        string `json:"host"`
+	AudioPort   int    `json:"audio_port,omitempty"`
+	VideoPort   int    `json:"video_port,omitempty"`
+	Secret      string `json:"secret,omitempty"`
+}
+
+// RtpForwardResponse describes the forwarder created by RtpForward.
+type RtpForwardResponse struct {
+	Room        uint64 `json:"room"`
+	PublisherID uint64 `json:"publisher_id"`
+	StreamID    uint64 `json:"stream_id"`
+}
+
+// RtpForward starts forwarding a publisher's RTP to an external host.
+func (c *Client) RtpForward(ctx context.Context, req RtpForwardRequest) (*RtpForwardResponse, error) {
+	body := struct {
+		Request string `json:"request"`
+		RtpForwardRequest
+	}{"rtp_forward", req}
+
+	resp, err := c.Handle.Request(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var out RtpForwardResponse
+	if err := wire.Decode(resp.Plugindata.Data, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// StopRtpForward stops a forwarder started with RtpForward.
+func (c *Client) StopRtpForward(ctx context.Context, room, publisherID, streamID uint64, secret string) error {
+	body := map[string]interface{}{
+		"request":      "stop_rtp_forward",
+		"room":         room,
+		"publisher_id": publisherID,
+		"stream_id":    streamID,
+	}
+	if secret != "" {
+		body["secret"] = secret
+	}
+	_, err := c.Handle.Request(ctx, body)
+	return err
+}
+
+// EnableRecording turns recording of room on or off.
+func (c *Client) EnableRecording(ctx context.Context, room uint64, record bool, secret string) error {
+	body := map[string]interface{}{"request": "enable_recording", "room": room, "record": record}
+	if secret != "" {
+		body["secret"] = secret
+	}
+	_, err := c.Handle.Request(ctx, body)
+	return err
+}
+
+// Event is implemented by every typed VideoRoom event DemuxEvent can
+// produce.
+type Event interface {
+	isVideoRoomEvent()
+}
+
+// PublisherJoined is fired when a VideoRoom "event" lists one or more
+// publishers newly visible in a room.
+type PublisherJoined struct {
+	Room       uint64
+	Publishers []Participant
+}
+
+func (PublisherJoined) isVideoRoomEvent() {}
+
+// PublisherLeft is fired when a publisher leaves a room.
+type PublisherLeft struct {
+	Room uint64
+	ID   uint64
+}
+
+func (PublisherLeft) isVideoRoomEvent() {}
+
+// SubscriberAttached is fired once a subscriber Handle is attached to a
+// publisher's feed.
+type SubscriberAttached struct {
+	Room uint64
+	ID   uint64
+}
+
+func (SubscriberAttached) isVideoRoomEvent() {}
+
+// Talking is fired by Janus's audio level detection when a publisher
+// starts talking.
+type Talking struct {
+	Room uint64
+	ID   uint64
+}
+
+func (Talking) isVideoRoomEvent() {}
+
+// StoppedTalking is fired when a publisher that was Talking goes quiet.
+type StoppedTalking struct {
+	Room uint64
+	ID   uint64
+}
+
+func (StoppedTalking) isVideoRoomEvent() {}
+
+// DemuxEvent inspects a raw message received on Handle.Events and, if
+// it's a VideoRoom plugin event this package knows how to interpret,
+// returns its typed form. ok is false for anything else (Acks, events
+// from a different plugin), so callers can ignore it.
+func DemuxEvent(msg interface{}) (ev Event, ok bool) {
+	event, isEvent := msg.(*janus.EventMsg)
+	if !isEvent || event.Plugindata.Plugin != Plugin {
+		return nil, false
+	}
+
+	var kind struct {
+		VideoRoom string `json:"videoroom"`
+	}
+	if err := wire.Decode(event.Plugindata.Data, &kind); err != nil {
+		return nil, false
+	}
+
+	switch kind.VideoRoom {
+	case "event":
+		var body struct {
+			Room       uint64          `json:"room"`
+			Publishers []Participant   `json:"publishers"`
+			Leaving    json.RawMessage `json:"leaving,omitempty"`
+		}
+		if err := wire.Decode(event.Plugindata.Data, &body); err != nil {
+			return nil, false
+		}
+		if len(body.Publishers) > 0 {
+			return PublisherJoined{Room: body.Room, Publishers: body.Publishers}, true
+		}
+		if len(body.Leaving) > 0 {
+			var id uint64
+			if err := json.Unmarshal(body.Leaving, &id); err == nil {
+				return PublisherLeft{Room: body.Room, ID: id}, true
+			}
+		}
+	case "attached":
+		var body struct {
+			Room uint64 `json:"room"`
+			ID   uint64 `json:"id"`
+		}
+		if err := wire.Decode(event.Plugindata.Data, &body); err == nil {
+			return SubscriberAttached(body), true
+		}
+	case "talking":
+		var body struct {
+			Room uint64 `json:"room"`
+			ID   uint64 `json:"id"`
+		}
+		if err := wire.Decode(event.Plugindata.Data, &body); err == nil {
+			return Talking(body), true
+		}
+	case "stopped-talking":
+		var body struct {
+			Room uint64 `json:"room"`
+			ID   uint64 `json:"id"`
+		}
+		if err := wire.Decode(event.Plugindata.Data, &body); err == nil {
+			return StoppedTalking(body), true
+		}
+	}
+
+	return nil, false
+}
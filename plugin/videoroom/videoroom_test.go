@@ -0,0 +1,68 @@
+package videoroom
+
+import (
+	"testing"
+
+	janus "github.com/jafzaf/janus-go"
+)
+
+func newEventMsg(data map[string]interface{}) interface{} {
+	return &janus.EventMsg{
+		Plugindata: janus.PluginData{Plugin: Plugin, Data: data},
+	}
+}
+
+func TestDemuxEventPublisherJoinedMultiple(t *testing.T) {
+	msg := newEventMsg(map[string]interface{}{
+		"videoroom": "event",
+		"room":      uint64(1234),
+		"publishers": []interface{}{
+			map[string]interface{}{"id": uint64(1), "display": "alice"},
+			map[string]interface{}{"id": uint64(2), "display": "bob"},
+		},
+	})
+
+	ev, ok := DemuxEvent(msg)
+	if !ok {
+		t.Fatal("DemuxEvent returned ok=false for a publishers event")
+	}
+	joined, isJoined := ev.(PublisherJoined)
+	if !isJoined {
+		t.Fatalf("got %T, want PublisherJoined", ev)
+	}
+	if len(joined.Publishers) != 2 {
+		t.Fatalf("Publishers = %v, want 2 entries", joined.Publishers)
+	}
+	if joined.Publishers[0].ID != 1 || joined.Publishers[1].ID != 2 {
+		t.Fatalf("Publishers = %+v, want ids [1 2]", joined.Publishers)
+	}
+}
+
+func TestDemuxEventPublisherLeft(t *testing.T) {
+	msg := newEventMsg(map[string]interface{}{
+		"videoroom": "event",
+		"room":      uint64(1234),
+		"leaving":   uint64(42),
+	})
+
+	ev, ok := DemuxEvent(msg)
+	if !ok {
+		t.Fatal("DemuxEvent returned ok=false for a leaving event")
+	}
+	left, isLeft := ev.(PublisherLeft)
+	if !isLeft {
+		t.Fatalf("got %T, want PublisherLeft", ev)
+	}
+	if left.ID != 42 {
+		t.Fatalf("ID = %d, want 42", left.ID)
+	}
+}
+
+func TestDemuxEventIgnoresOtherPlugins(t *testing.T) {
+	msg := &janus.EventMsg{
+		Plugindata: janus.PluginData{Plugin: "janus.plugin.audiobridge", Data: map[string]interface{}{}},
+	}
+	if _, ok := DemuxEvent(msg); ok {
+		t.Fatal("DemuxEvent returned ok=true for a message from a different plugin")
+	}
+}
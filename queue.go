@@ -0,0 +1,154 @@
+package janus
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy controls what an EventQueue does when Push is called
+// against a full queue.
+type OverflowPolicy int
+
+const (
+	// DropOldest discards the oldest buffered message to make room for
+	// the new one. This is the default used for Session.Events and
+	// Handle.Events.
+	DropOldest OverflowPolicy = iota
+
+	// DropNewest discards the message being pushed, leaving the queue
+	// unchanged.
+	DropNewest
+
+	// Block makes Push wait until the consumer has room. Only use this
+	// if the consumer is guaranteed to keep draining the queue, since a
+	// stalled consumer will stall Gateway.recv for every Session/Handle
+	// sharing the connection.
+	Block
+)
+
+// EventQueue is a bounded, single-writer queue of messages destined for a
+// Session or Handle's Events channel. Gateway.recv pushes into it
+// directly and a single goroutine owned by the queue applies
+// OverflowPolicy and feeds the channel applications consume from, instead
+// of the old pattern of spawning a goroutine per message and dropping it
+// after a fixed 1-second stall.
+type EventQueue struct {
+	policy  OverflowPolicy
+	in      chan interface{}
+	out     chan interface{}
+	dropped uint64
+	drained chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewEventQueue creates a queue that buffers up to capacity messages
+// before policy kicks in.
+func NewEventQueue(capacity int, policy OverflowPolicy) *EventQueue {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	q := &EventQueue{
+		policy:  policy,
+		in:      make(chan interface{}),
+		out:     make(chan interface{}, capacity),
+		drained: make(chan struct{}, 1),
+	}
+	go q.run()
+	return q
+}
+
+// Events returns the channel applications should range/select over to
+// receive delivered messages.
+func (q *EventQueue) Events() <-chan interface{} {
+	return q.out
+}
+
+// Push enqueues msg, applying policy if the queue is full. It never
+// spawns a goroutine and, except under Block, never waits on the
+// consumer. Push is a no-op once Close has been called, so a Push
+// racing a concurrent Close (e.g. Gateway.recv delivering a final
+// event while Handle.Detach tears the handle down) can never panic by
+// sending on a closed channel.
+func (q *EventQueue) Push(msg interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.in <- msg
+}
+
+// Close stops accepting further Push calls and closes the channel
+// returned by Events once any already-queued messages have been
+// delivered. Close is idempotent: calling it more than once (e.g. from
+// both Handle.Detach and a concurrent terminal-error cleanup) is safe.
+func (q *EventQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	close(q.in)
+}
+
+// Len reports how many messages are currently buffered, waiting to be
+// received.
+func (q *EventQueue) Len() int {
+	return len(q.out)
+}
+
+// Dropped reports how many messages this queue has discarded under
+// DropOldest/DropNewest because the consumer fell behind.
+func (q *EventQueue) Dropped() uint64 {
+	return atomic.LoadUint64(&q.dropped)
+}
+
+// Drained receives a value when, right after a pushed message has been
+// handed to the Events channel, that channel is found empty (the consumer
+// already took it). A backlog the consumer drains later, between pushes,
+// sends nothing on Drained, and at most one value is buffered.
+func (q *EventQueue) Drained() <-chan struct{} {
+	return q.drained
+}
+
+func (q *EventQueue) run() {
+	for msg := range q.in {
+		q.deliver(msg)
+		if len(q.out) == 0 {
+			select {
+			case q.drained <- struct{}{}:
+			default:
+			}
+		}
+	}
+	close(q.out)
+}
+
+func (q *EventQueue) deliver(msg interface{}) {
+	switch q.policy {
+	case Block:
+		q.out <- msg
+	case DropNewest:
+		select {
+		case q.out <- msg:
+		default:
+			atomic.AddUint64(&q.dropped, 1)
+		}
+	default: // DropOldest
+		for {
+			select {
+			case q.out <- msg:
+				return
+			default:
+			}
+			select {
+			case <-q.out:
+				atomic.AddUint64(&q.dropped, 1)
+			default:
+			}
+		}
+	}
+}
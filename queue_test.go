@@ -0,0 +1,126 @@
+package janus
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventQueuePushAfterClose(t *testing.T) {
+	q := NewEventQueue(1, DropOldest)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			q.Push(i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		q.Close()
+	}()
+	wg.Wait()
+}
+
+func TestEventQueueDoubleClose(t *testing.T) {
+	q := NewEventQueue(1, DropOldest)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			defer wg.Done()
+			q.Close()
+		}()
+	}
+	wg.Wait()
+}
+
+// waitForDropped polls q.Dropped() until it reaches want. Push only hands
+// a message off to the queue's single-writer goroutine; it doesn't wait
+// for that goroutine to finish applying the overflow policy, so a test
+// that pushes past capacity needs to wait for the counter rather than
+// read it immediately after the last Push returns.
+func waitForDropped(t *testing.T, q *EventQueue, want uint64) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if q.Dropped() == want {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Dropped() = %d, want %d", q.Dropped(), want)
+}
+
+func TestEventQueueDropOldest(t *testing.T) {
+	q := NewEventQueue(2, DropOldest)
+	q.Push(1)
+	q.Push(2)
+	q.Push(3) // should drop 1
+	waitForDropped(t, q, 1)
+
+	got := []interface{}{<-q.Events(), <-q.Events()}
+	if got[0] != 2 || got[1] != 3 {
+		t.Fatalf("got %v, want [2 3]", got)
+	}
+}
+
+func TestEventQueueDropNewest(t *testing.T) {
+	q := NewEventQueue(2, DropNewest)
+	q.Push(1)
+	q.Push(2)
+	q.Push(3) // should be discarded
+	waitForDropped(t, q, 1)
+
+	got := []interface{}{<-q.Events(), <-q.Events()}
+	if got[0] != 1 || got[1] != 2 {
+		t.Fatalf("got %v, want [1 2]", got)
+	}
+}
+
+func TestEventQueueBlock(t *testing.T) {
+	q := NewEventQueue(1, Block)
+	// The first two pushes are absorbed immediately: one fills the
+	// buffered out channel, the other is picked up by the run
+	// goroutine and then blocks trying to deliver it. A third push
+	// has nowhere to hand off to until the consumer drains the queue.
+	q.Push(1)
+	q.Push(2)
+
+	done := make(chan struct{})
+	go func() {
+		q.Push(3)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Push under Block policy returned before consumer drained the queue")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	<-q.Events()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Push under Block policy never unblocked after consumer drained the queue")
+	}
+}
+
+func TestEventQueueCloseDrainsQueuedMessages(t *testing.T) {
+	q := NewEventQueue(4, DropOldest)
+	q.Push(1)
+	q.Push(2)
+	q.Close()
+
+	var got []interface{}
+	for msg := range q.Events() {
+		got = append(got, msg)
+	}
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fatalf("got %v, want [1 2]", got)
+	}
+}
@@ -0,0 +1,324 @@
+package janus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"nhooyr.io/websocket"
+)
+
+func newTestGateway() *Gateway {
+	gateway := new(Gateway)
+	gateway.transactions = make(map[xid.ID]chan interface{})
+	gateway.transactionsUsed = make(map[xid.ID]bool)
+	gateway.Sessions = make(map[uint64]*Session)
+	gateway.connCond = sync.NewCond(&gateway.connMu)
+	gateway.state = stateConnected
+	return gateway
+}
+
+func TestJitterBounds(t *testing.T) {
+	d := 100 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		got := jitter(d)
+		if got < d/2 || got >= d+d/2 {
+			t.Fatalf("jitter(%s) = %s, want in [%s, %s)", d, got, d/2, d+d/2)
+		}
+	}
+}
+
+func TestFailPendingTransactionsNotifiesAndClears(t *testing.T) {
+	gateway := newTestGateway()
+
+	id := xid.New()
+	ch := make(chan interface{})
+	gateway.transactions[id] = ch
+
+	ready := make(chan struct{})
+	done := make(chan interface{})
+	go func() {
+		close(ready)
+		done <- <-ch
+	}()
+	<-ready
+	// Give the goroutine a chance to actually reach the blocking
+	// receive before the non-blocking notify below fires.
+	time.Sleep(10 * time.Millisecond)
+
+	gateway.failPendingTransactions(errors.New("connection reset"))
+
+	select {
+	case msg := <-done:
+		errMsg, ok := msg.(*ErrorMsg)
+		if !ok {
+			t.Fatalf("got %T, want *ErrorMsg", msg)
+		}
+		if errMsg.Code() != ErrTransportSpecific {
+			t.Fatalf("Code() = %d, want %d", errMsg.Code(), ErrTransportSpecific)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pending transaction was never notified")
+	}
+
+	gateway.Lock()
+	n := len(gateway.transactions)
+	gateway.Unlock()
+	if n != 0 {
+		t.Fatalf("len(transactions) = %d, want 0", n)
+	}
+}
+
+func TestReconnectDisabledReturnsFalse(t *testing.T) {
+	gateway := newTestGateway()
+	// ReconnectOptions is the zero value: MaxRetries == 0 disables reconnection.
+	if gateway.reconnect(context.Background(), errors.New("boom")) {
+		t.Fatal("reconnect() = true, want false when ReconnectOptions is unset")
+	}
+	if gateway.state != stateConnected {
+		t.Fatalf("state = %v, want stateConnected", gateway.state)
+	}
+}
+
+func TestCloseMarksGatewayClosedAndPreventsReconnect(t *testing.T) {
+	gateway := newTestGateway()
+	gateway.transport = &fakeTransport{}
+	gateway.ReconnectOptions = ReconnectOptions{
+		MaxRetries:  3,
+		BaseBackoff: time.Millisecond,
+	}
+
+	var dialed int
+	gateway.dial = func(ctx context.Context) (Transport, error) {
+		dialed++
+		return &fakeTransport{}, nil
+	}
+
+	if err := gateway.Close(websocket.StatusNormalClosure, "bye"); err != nil {
+		t.Fatalf("Close returned error: %s", err)
+	}
+
+	// superviseTransport calls reconnect with whatever error Close's
+	// transport.Close() produced in the blocked ping/recv loop.
+	if gateway.reconnect(context.Background(), errors.New("use of closed connection")) {
+		t.Fatal("reconnect() = true, want false after an intentional Close")
+	}
+	if dialed != 0 {
+		t.Fatalf("dial called %d times, want 0: a deliberate Close must not trigger a redial", dialed)
+	}
+	if gateway.state != stateClosed {
+		t.Fatalf("state = %v, want stateClosed", gateway.state)
+	}
+}
+
+func TestCloseDuringRedialWinsOverReconnectSuccess(t *testing.T) {
+	gateway := newTestGateway()
+	gateway.transport = &fakeTransport{}
+	gateway.ReconnectOptions = ReconnectOptions{
+		MaxRetries:  1,
+		BaseBackoff: time.Millisecond,
+	}
+
+	dialing := make(chan struct{})
+	proceed := make(chan struct{})
+	redialed := &fakeTransport{}
+	gateway.dial = func(ctx context.Context) (Transport, error) {
+		close(dialing)
+		<-proceed
+		return redialed, nil
+	}
+
+	done := make(chan bool, 1)
+	go func() { done <- gateway.reconnect(context.Background(), errors.New("boom")) }()
+
+	<-dialing
+	if err := gateway.Close(websocket.StatusNormalClosure, "bye"); err != nil {
+		t.Fatalf("Close returned error: %s", err)
+	}
+	close(proceed)
+
+	if ok := <-done; ok {
+		t.Fatal("reconnect() = true, want false: Close should win a race with an in-flight redial")
+	}
+	if gateway.state != stateClosed {
+		t.Fatalf("state = %v, want stateClosed", gateway.state)
+	}
+	if !redialed.closed {
+		t.Fatal("the transport dialed after Close was never closed, leaking its connection")
+	}
+}
+
+func TestReconnectExhaustsRetries(t *testing.T) {
+	gateway := newTestGateway()
+	gateway.ReconnectOptions = ReconnectOptions{
+		MaxRetries:  3,
+		BaseBackoff: time.Millisecond,
+	}
+
+	var attempts int
+	gateway.dial = func(ctx context.Context) (Transport, error) {
+		attempts++
+		return nil, errors.New("dial failed")
+	}
+
+	if gateway.reconnect(context.Background(), errors.New("boom")) {
+		t.Fatal("reconnect() = true, want false once retries are exhausted")
+	}
+	if attempts != 3 {
+		t.Fatalf("dial called %d times, want 3", attempts)
+	}
+	if gateway.state != stateClosed {
+		t.Fatalf("state = %v, want stateClosed", gateway.state)
+	}
+}
+
+func TestReconnectSucceedsAndClosesOldTransport(t *testing.T) {
+	gateway := newTestGateway()
+	gateway.ReconnectOptions = ReconnectOptions{
+		MaxRetries:  1,
+		BaseBackoff: time.Millisecond,
+	}
+
+	old := &fakeTransport{}
+	gateway.transport = old
+	gateway.dial = func(ctx context.Context) (Transport, error) {
+		return &fakeTransport{}, nil
+	}
+
+	if !gateway.reconnect(context.Background(), errors.New("boom")) {
+		t.Fatal("reconnect() = false, want true")
+	}
+	if gateway.state != stateConnected {
+		t.Fatalf("state = %v, want stateConnected", gateway.state)
+	}
+	if !old.closed {
+		t.Fatal("previous transport was never closed, leaking its connection")
+	}
+}
+
+// TestReconnectReclaimsSessionWithoutDeadlock guards against resume's
+// internal claim/create/attach requests routing through waitForConnected:
+// since they run on the redialLoop goroutine itself, before reconnect
+// flips the Gateway out of stateReconnecting, waiting there would block
+// forever on a connCond nothing ever broadcasts.
+func TestReconnectReclaimsSessionWithoutDeadlock(t *testing.T) {
+	gateway := newTestGateway()
+	gateway.ReconnectOptions = ReconnectOptions{
+		MaxRetries:  1,
+		BaseBackoff: time.Millisecond,
+	}
+
+	gateway.Sessions[7] = &Session{ID: 7, gateway: gateway, Handles: make(map[uint64]*Handle)}
+
+	gateway.dial = func(ctx context.Context) (Transport, error) {
+		return &claimingTransport{gateway: gateway}, nil
+	}
+
+	done := make(chan bool, 1)
+	go func() { done <- gateway.reconnect(context.Background(), errors.New("boom")) }()
+
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Fatal("reconnect() = false, want true")
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("reconnect() never returned: a populated Sessions map deadlocked the redial")
+	}
+
+	if gateway.state != stateConnected {
+		t.Fatalf("state = %v, want stateConnected", gateway.state)
+	}
+}
+
+// TestSessionIDAccessDuringReattachIsRaceFree guards against reattach's
+// session.ID rewrite racing a concurrent sender reading it the way
+// Session.send does (caught by `go test -race` before session.id()/
+// handle.id() existed).
+func TestSessionIDAccessDuringReattachIsRaceFree(t *testing.T) {
+	gateway := newTestGateway()
+	gateway.transport = &claimingTransport{gateway: gateway}
+
+	session := &Session{ID: 7, gateway: gateway, Handles: make(map[uint64]*Handle)}
+	gateway.Sessions[7] = session
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_ = session.id()
+			}
+		}
+	}()
+
+	gateway.reattach(context.Background(), session)
+
+	close(stop)
+	wg.Wait()
+
+	if session.id() == 7 {
+		t.Fatal("reattach() didn't rewrite session.ID")
+	}
+}
+
+// claimingTransport simulates a server that accepts resume's claim
+// request for a reclaimed session, replying to every Write by looking up
+// the matching transaction channel and feeding it a success/ack.
+type claimingTransport struct {
+	fakeTransport
+	gateway *Gateway
+}
+
+func (t *claimingTransport) Write(ctx context.Context, data []byte) error {
+	var req struct {
+		Janus       string `json:"janus"`
+		Transaction string `json:"transaction"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+
+	id, err := xid.FromString(req.Transaction)
+	if err != nil {
+		return err
+	}
+
+	t.gateway.Lock()
+	ch := t.gateway.transactions[id]
+	t.gateway.Unlock()
+	if ch == nil {
+		return nil
+	}
+
+	var reply interface{} = &SuccessMsg{}
+	if req.Janus == "claim" {
+		reply = &AckMsg{}
+	}
+	go func() { ch <- reply }()
+	return nil
+}
+
+type fakeTransport struct {
+	closed bool
+}
+
+func (t *fakeTransport) Write(ctx context.Context, data []byte) error { return nil }
+func (t *fakeTransport) Read(ctx context.Context) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+func (t *fakeTransport) Close(code websocket.StatusCode, reason string) error {
+	t.closed = true
+	return nil
+}
+func (t *fakeTransport) Ping(ctx context.Context) error { return nil }
@@ -0,0 +1,60 @@
+package janus
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a point-in-time snapshot of Gateway counters. Its fields are
+// plain enough to expose directly through expvar.Func or a
+// prometheus.Collector without another translation layer.
+type Stats struct {
+	// MessagesReceived is the total number of messages read off the
+	// transport since Connect.
+	MessagesReceived uint64
+
+	// MessagesDropped counts messages discarded by a Session or Handle's
+	// EventQueue under DropOldest/DropNewest because the consumer fell
+	// behind.
+	MessagesDropped struct {
+		Session uint64
+		Handle  uint64
+	}
+
+	// TransactionsInFlight is the number of requests currently awaiting a
+	// response from the Gateway.
+	TransactionsInFlight int
+
+	// ReconnectAttempts is the total number of redial attempts made by
+	// the reconnect state machine, across every reconnect episode.
+	ReconnectAttempts uint64
+
+	// PingRTT is how long the most recent successful Transport.Ping call
+	// took to return. The WebSocket transport's Ping returns once the ping
+	// frame is written, without waiting for a pong, and HTTPTransport's
+	// Ping is a no-op, so this is not a network round-trip time.
+	PingRTT time.Duration
+}
+
+// Stats returns a snapshot of the Gateway's current counters.
+func (gateway *Gateway) Stats() Stats {
+	var s Stats
+	s.MessagesReceived = atomic.LoadUint64(&gateway.messagesReceived)
+	s.ReconnectAttempts = atomic.LoadUint64(&gateway.reconnectAttempts)
+	s.PingRTT = time.Duration(atomic.LoadInt64(&gateway.pingRTTNanos))
+
+	gateway.Lock()
+	s.TransactionsInFlight = len(gateway.transactions)
+	for _, session := range gateway.Sessions {
+		s.MessagesDropped.Session += session.Events.Dropped()
+
+		session.Lock()
+		for _, handle := range session.Handles {
+			s.MessagesDropped.Handle += handle.Events.Dropped()
+		}
+		session.Unlock()
+	}
+	gateway.Unlock()
+
+	return s
+}
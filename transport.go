@@ -0,0 +1,90 @@
+package janus
+
+import (
+	"context"
+
+	"nhooyr.io/websocket"
+)
+
+// Transport abstracts the wire connection a Gateway uses to reach Janus, so
+// that the default WebSocket binding and Janus's plain HTTP/long-poll
+// binding can share the same Gateway/Session/Handle plumbing.
+type Transport interface {
+	// Write sends a single JSON-encoded request.
+	Write(ctx context.Context, data []byte) error
+
+	// Read blocks until the next response or event is available and
+	// returns its raw JSON bytes.
+	Read(ctx context.Context) ([]byte, error)
+
+	// Close tears down the transport. code/reason follow WebSocket close
+	// semantics; transports with no equivalent (HTTP) ignore them.
+	Close(code websocket.StatusCode, reason string) error
+
+	// Ping keeps the transport alive. Transports with no equivalent
+	// (HTTP) treat this as a no-op.
+	Ping(ctx context.Context) error
+}
+
+// Redialer is implemented by a Transport that knows how to establish a
+// fresh connection of its own kind, so ReconnectOptions can redial it the
+// same way the default WebSocket transport redials wsURL. A Transport
+// passed to WithTransport that doesn't implement Redialer has no defined
+// way to reconnect; combining it with ReconnectOptions makes every redial
+// attempt fail outright instead of silently handing back the same
+// (likely already-closed) instance and pretending the reconnect worked.
+type Redialer interface {
+	Redial(ctx context.Context) (Transport, error)
+}
+
+// Option configures optional behavior for Connect.
+type Option func(*connectConfig)
+
+type connectConfig struct {
+	transport Transport
+}
+
+// WithTransport selects a non-default Transport for Connect, e.g.
+// janus.NewHTTPTransport for environments where WebSocket upgrades are
+// blocked. Omitting this option dials wsURL over WebSocket, as before.
+//
+// ReconnectOptions redials the default WebSocket transport by reopening
+// wsURL; a custom Transport must implement Redialer for ReconnectOptions
+// to redial it the same way. Without Redialer, ReconnectOptions still
+// retries (and fails) MaxRetries times rather than giving the Transport
+// up for good on the first transport error.
+func WithTransport(t Transport) Option {
+	return func(c *connectConfig) { c.transport = t }
+}
+
+// wsTransport is the default Transport, backed by a single long-lived
+// WebSocket connection.
+type wsTransport struct {
+	conn *websocket.Conn
+}
+
+func dialWebSocket(ctx context.Context, wsURL string) (*wsTransport, error) {
+	opts := &websocket.DialOptions{Subprotocols: []string{"janus-protocol"}}
+	conn, _, err := websocket.Dial(ctx, wsURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &wsTransport{conn: conn}, nil
+}
+
+func (t *wsTransport) Write(ctx context.Context, data []byte) error {
+	return t.conn.Write(ctx, websocket.MessageText, data)
+}
+
+func (t *wsTransport) Read(ctx context.Context) ([]byte, error) {
+	_, data, err := t.conn.Read(ctx)
+	return data, err
+}
+
+func (t *wsTransport) Close(code websocket.StatusCode, reason string) error {
+	return t.conn.Close(code, reason)
+}
+
+func (t *wsTransport) Ping(ctx context.Context) error {
+	return t.conn.Write(ctx, pingMessage, []byte{})
+}
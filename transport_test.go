@@ -0,0 +1,60 @@
+package janus
+
+import (
+	"context"
+	"testing"
+)
+
+// TestConnectRedialFailsWithoutRedialer guards against the dial closure
+// Connect builds for a custom Transport silently handing back the same
+// instance on every redial attempt (pretending a reconnect worked against
+// a Transport with no defined way to actually redial).
+func TestConnectRedialFailsWithoutRedialer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gateway, g, err := Connect(ctx, "", "", WithTransport(&fakeTransport{}))
+	if err != nil {
+		t.Fatalf("Connect returned error: %s", err)
+	}
+
+	if _, err := gateway.dial(context.Background()); err == nil {
+		t.Fatal("dial() = nil error, want one: a Transport with no Redial method can't be redialed")
+	}
+
+	cancel()
+	g.Wait()
+}
+
+// redialingTransport implements Redialer, returning a fresh instance each
+// time instead of handing back itself.
+type redialingTransport struct {
+	fakeTransport
+	redials int
+}
+
+func (t *redialingTransport) Redial(ctx context.Context) (Transport, error) {
+	t.redials++
+	return &redialingTransport{}, nil
+}
+
+func TestConnectRedialUsesRedialer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gateway, g, err := Connect(ctx, "", "", WithTransport(&redialingTransport{}))
+	if err != nil {
+		t.Fatalf("Connect returned error: %s", err)
+	}
+
+	redialed, err := gateway.dial(context.Background())
+	if err != nil {
+		t.Fatalf("dial() returned error: %s", err)
+	}
+	if _, ok := redialed.(*redialingTransport); !ok {
+		t.Fatalf("dial() = %T, want *redialingTransport", redialed)
+	}
+
+	cancel()
+	g.Wait()
+}